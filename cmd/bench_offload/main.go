@@ -51,7 +51,14 @@ func main() {
 	// Verify
 	fmt.Println("Verifying...")
 	verifyStart := time.Now()
-	success, err := offloader.Verify()
+	verifyChan := make(chan offload.ProgressInfo, 100)
+	go func() {
+		for range verifyChan {
+			// drain
+		}
+	}()
+	success, err := offloader.Verify(context.Background(), verifyChan)
+	close(verifyChan)
 	verifyTime := time.Since(verifyStart)
 
 	if err != nil || !success {
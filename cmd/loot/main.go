@@ -3,8 +3,10 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"loot/internal/config"
+	_ "loot/internal/metadata/parsers" // Register header parsers
 	"loot/internal/offload"
 	"loot/internal/ui"
 
@@ -16,15 +18,29 @@ import (
 var version = "dev"
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		os.Exit(runVerify(os.Args[2:]))
+	}
+
 	cfg, err := config.ParseFlags(version)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Structured destinations: every root gets the expanded template appended
+	if !cfg.Interactive {
+		offload.PrefillFromCard(cfg)
+		if err := offload.ApplyDestTemplate(cfg, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Dry Run
 	if cfg.DryRun {
-		o := offload.NewOffloaderWithConfig(cfg, cfg.Source, cfg.Destination)
+		o := offload.NewOffloaderWithConfig(cfg, cfg.Source, cfg.Destinations...)
 		res, err := o.DryRun()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error during dry run: %v\n", err)
@@ -35,14 +51,39 @@ func main() {
 		fmt.Printf("Source: %s\n", res.Source)
 		fmt.Printf("Files found: %d\n", len(res.Files))
 		fmt.Printf("Total Size: %s\n", offload.FormatBytes(uint64(res.TotalSize)))
+		if cfg.DestTemplate != "" {
+			fmt.Printf("Template: %s\n", cfg.DestTemplate)
+		}
+		if res.Card != nil {
+			fmt.Printf("Card: %s\n", res.Card)
+		}
+		if len(res.Issues) > 0 {
+			verdict := "job will be blocked (use --ignore-structure to override)"
+			if cfg.IgnoreStructure {
+				verdict = "overridden with --ignore-structure"
+			}
+			fmt.Printf("\n⚠️  Card structure: %d issue(s), %s\n", len(res.Issues), verdict)
+			for _, issue := range res.Issues {
+				fmt.Printf("  - %s\n", issue)
+			}
+		}
+		if len(res.Excluded) > 0 {
+			fmt.Printf("\nExcluded: %d item(s)\n", len(res.Excluded))
+			for _, e := range res.Excluded {
+				fmt.Printf("  - %s (%s)\n", e.RelPath, e.Reason)
+			}
+		}
 		fmt.Println("\nDestinations:")
 		for _, dest := range res.Destinations {
-			status := "✅ OK"
-			if !dest.CanFit {
+			status, free := "✅ OK", offload.FormatBytes(dest.FreeSpace)
+			switch {
+			case !dest.SpaceKnown:
+				status, free = "❔ UNKNOWN", "unknown"
+			case !dest.CanFit:
 				status = "❌ INSUFFICIENT SPACE"
 			}
 			fmt.Printf("  - %s\n", dest.Path)
-			fmt.Printf("    Free Space: %s\n", offload.FormatBytes(dest.FreeSpace))
+			fmt.Printf("    Free Space: %s\n", free)
 			fmt.Printf("    Status: %s\n", status)
 		}
 		return
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"loot/internal/mhl"
+	"loot/internal/output"
+)
+
+// runVerify implements `loot verify [flags] <destination>` and returns the exit code
+func runVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	mhlPath := fs.String("mhl", "", "MHL file to verify against (default: <destination>.mhl)")
+	jsonOutput := fs.Bool("json", false, "Output results in JSON format")
+	quiet := fs.Bool("quiet", false, "Suppress all output except errors")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  loot verify [flags] <destination>\n\n")
+		fmt.Fprintf(os.Stderr, "Re-checks a destination against its MHL and ascmhl/ history.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	root := fs.Arg(0)
+
+	manifest, err := mhl.LoadManifest(root, *mhlPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	result, err := mhl.VerifyRoot(root, manifest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error during verify: %v\n", err)
+		return 1
+	}
+
+	if *jsonOutput {
+		output.PrintVerifyJSON(*result)
+	} else if !*quiet {
+		output.PrintVerifyHuman(*result)
+	}
+
+	if result.Status != "success" {
+		return 1
+	}
+	return 0
+}
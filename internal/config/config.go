@@ -4,6 +4,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type HashAlgorithm string
@@ -14,12 +16,20 @@ const (
 	AlgoSHA256   HashAlgorithm = "sha256"
 )
 
+// Symlink policies
+const (
+	SymlinksCopy   = "copy"   // Recreate the link on the destinations
+	SymlinksFollow = "follow" // Copy what the link points to
+	SymlinksSkip   = "skip"   // Leave links out (listed as excluded)
+	SymlinksError  = "error"  // Refuse sources that contain links
+)
+
 // Config holds all configuration for LOOT
 type Config struct {
 	// Operation mode
-	Interactive bool
-	Source      string
-	Destination string
+	Interactive  bool
+	Source       string
+	Destinations []string
 
 	// Output options
 	JSONOutput bool
@@ -31,28 +41,111 @@ type Config struct {
 	DualHash  bool // Calculate both xxhash64 and MD5
 
 	// Verification
-	NoVerify bool
+	NoVerify    bool
+	VerifyTimes bool // Also check that copies kept the source modification time
+
+	// File metadata carried over to copies (all preserved by default)
+	NoPreserveTimes bool // Access/modification times
+	NoPreserveMode  bool // Permission bits
+	NoXattrs        bool // Extended attributes
 
 	// Performance
 	BufferSize  int // in bytes
 	Concurrency int // Number of parallel file copies
 
+	// Bandwidth limits in MB/s (0 = unlimited): for the whole job, and per
+	// destination root (a cap on a folder also covers folders below it)
+	BWLimit      float64
+	DestBWLimits map[string]float64
+
 	// Dry run
 	DryRun bool
 
 	// Resume
 	SkipExisting bool
 
+	// Failure handling: drop a failing destination and keep copying to the others
+	IsolateFailures bool
+
+	// Copy a card even if the structure check finds missing segments/sidecars
+	IgnoreStructure bool
+
+	// What to do with symbolic links on the source: SymlinksCopy (default),
+	// SymlinksFollow, SymlinksSkip or SymlinksError
+	Symlinks string
+
+	// Include/exclude rules: globs on the name, globs on the relative path
+	// (if they contain '/'), or regexps with a "re:" prefix
+	Include           []string
+	Exclude           []string
+	NoDefaultExcludes bool // Copy OS system files (.DS_Store, Thumbs.db...) too
+
 	// Metadata
 	JobName      string
 	Camera       string
 	Reel         string
+	ShootDay     string
 	MetadataMode string
 
+	// Destination folder template, e.g. "{project}/{date}/{camera}_{reel}/{card}"
+	DestTemplate string
+
+	// Preset applied from the config file (--preset), for display
+	Preset string
+
 	// Version info
 	Version string
 }
 
+// stringList is a repeatable string flag (e.g. --dest a --dest b)
+type stringList struct {
+	values *[]string
+}
+
+func (l stringList) String() string {
+	if l.values == nil {
+		return ""
+	}
+	return strings.Join(*l.values, ",")
+}
+
+func (l stringList) Set(v string) error {
+	*l.values = append(*l.values, v)
+	return nil
+}
+
+// limitMap is a repeatable PATH=MBps flag (e.g. --dest-bwlimit /mnt/nas=80)
+type limitMap struct {
+	values *map[string]float64
+}
+
+func (m limitMap) String() string {
+	if m.values == nil {
+		return ""
+	}
+	var parts []string
+	for path, mbps := range *m.values {
+		parts = append(parts, fmt.Sprintf("%s=%g", path, mbps))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (m limitMap) Set(v string) error {
+	i := strings.LastIndex(v, "=")
+	if i <= 0 {
+		return fmt.Errorf("expected PATH=MBps, got %q", v)
+	}
+	mbps, err := strconv.ParseFloat(v[i+1:], 64)
+	if err != nil || mbps < 0 {
+		return fmt.Errorf("invalid limit %q: expected MB/s >= 0", v[i+1:])
+	}
+	if *m.values == nil {
+		*m.values = make(map[string]float64)
+	}
+	(*m.values)[v[:i]] = mbps
+	return nil
+}
+
 // DefaultConfig returns config with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
@@ -61,6 +154,7 @@ func DefaultConfig() *Config {
 		DualHash:     false,
 		BufferSize:   4 * 1024 * 1024, // 4MB
 		Concurrency:  4,
+		Symlinks:     SymlinksCopy,
 		NoVerify:     false,
 		DryRun:       false,
 		JSONOutput:   false,
@@ -71,6 +165,15 @@ func DefaultConfig() *Config {
 	}
 }
 
+// ParseAlgorithm validates a hash algorithm name
+func ParseAlgorithm(name string) (HashAlgorithm, error) {
+	switch HashAlgorithm(name) {
+	case AlgoXXHash64, AlgoMD5, AlgoSHA256:
+		return HashAlgorithm(name), nil
+	}
+	return "", fmt.Errorf("invalid algorithm: %s (must be xxhash64, md5, or sha256)", name)
+}
+
 // ParseFlags parses command line arguments and returns Config
 func ParseFlags(version string) (*Config, error) {
 	cfg := DefaultConfig()
@@ -84,22 +187,38 @@ func ParseFlags(version string) (*Config, error) {
 	flag.BoolVar(&cfg.Quiet, "quiet", false, "Suppress all output except errors")
 	flag.BoolVar(&cfg.Verbose, "verbose", false, "Enable verbose logging")
 	flag.BoolVar(&cfg.NoVerify, "no-verify", false, "Skip verification after copy")
+	flag.BoolVar(&cfg.VerifyTimes, "verify-times", false, "Fail verification if a copy lost the source modification time")
+	flag.BoolVar(&cfg.NoPreserveTimes, "no-preserve-times", false, "Don't copy access/modification times")
+	flag.BoolVar(&cfg.NoPreserveMode, "no-preserve-mode", false, "Don't copy permission bits")
+	flag.BoolVar(&cfg.NoXattrs, "no-xattrs", false, "Don't copy extended attributes")
 	flag.BoolVar(&cfg.DryRun, "dry-run", false, "Simulate operation without copying")
 	flag.IntVar(&cfg.BufferSize, "buffer-size", 4*1024*1024, "Buffer size in bytes")
 	flag.IntVar(&cfg.Concurrency, "concurrency", 4, "Number of parallel file copies")
 	flag.IntVar(&cfg.Concurrency, "c", 4, "Number of parallel file copies (shorthand)")
+	flag.Float64Var(&cfg.BWLimit, "bwlimit", 0, "Bandwidth limit in MB/s for the whole job (0 = unlimited)")
+	flag.Var(limitMap{&cfg.DestBWLimits}, "dest-bwlimit", "Bandwidth limit for one destination as PATH=MBps (repeatable)")
 	flag.BoolVar(&cfg.SkipExisting, "skip-existing", false, "Skip files that exist at destination")
 	flag.BoolVar(&cfg.SkipExisting, "resume", false, "Resume interrupted transfer (alias for --skip-existing)")
+	flag.BoolVar(&cfg.IsolateFailures, "isolate-failures", false, "Drop a failing destination and keep copying to the others")
+	flag.StringVar(&cfg.Symlinks, "symlinks", SymlinksCopy, "Symlinks on the source: copy (as links), follow, skip, error")
+	flag.Var(stringList{&cfg.Include}, "include", "Only copy files matching this glob or re:regexp (repeatable)")
+	flag.Var(stringList{&cfg.Exclude}, "exclude", "Skip files and folders matching this glob or re:regexp (repeatable)")
+	flag.BoolVar(&cfg.NoDefaultExcludes, "no-default-excludes", false, "Also copy OS system files (.DS_Store, Thumbs.db, ._*...)")
+	flag.BoolVar(&cfg.IgnoreStructure, "ignore-structure", false, "Copy even if the card has missing segments, sidecars or zero-byte files")
 	flag.StringVar(&cfg.JobName, "job-name", "", "Job name for report metadata")
 	flag.StringVar(&cfg.Camera, "camera", "", "Camera identifier (e.g. 'A', 'B')")
 	flag.StringVar(&cfg.Reel, "reel", "", "Reel identifier (e.g. '001', 'A002')")
+	flag.StringVar(&cfg.ShootDay, "day", "", "Shoot day (e.g. 'D03'), for the {day} template token")
+	flag.StringVar(&cfg.DestTemplate, "dest-template", "", "Destination sub-folder template, e.g. '{project}/{date}/{camera}_{reel}/{card}'")
 	flag.StringVar(&cfg.MetadataMode, "metadata-mode", "hybrid", "Metadata extraction mode: hybrid (default), header, exiftool, off")
 
 	flag.StringVar(&cfg.Source, "source", "", "Source directory")
 	flag.StringVar(&cfg.Source, "s", "", "Source directory (shorthand)")
-	flag.StringVar(&cfg.Destination, "dest", "", "Destination directory")
-	flag.StringVar(&cfg.Destination, "d", "", "Destination directory (shorthand)")
-	flag.StringVar(&cfg.Destination, "destination", "", "Destination directory")
+	flag.Var(stringList{&cfg.Destinations}, "dest", "Destination directory (repeatable for multiple destinations)")
+	flag.Var(stringList{&cfg.Destinations}, "d", "Destination directory (shorthand, repeatable)")
+	flag.Var(stringList{&cfg.Destinations}, "destination", "Destination directory (repeatable)")
+
+	flag.StringVar(&cfg.Preset, "preset", "", "Apply a named preset from the config file")
 
 	versionFlag := flag.Bool("version", false, "Print version")
 	v := flag.Bool("v", false, "Print version (shorthand)")
@@ -113,14 +232,24 @@ func ParseFlags(version string) (*Config, error) {
 		fmt.Fprintf(os.Stderr, "LOOT - Professional Media Offload Tool\n\n")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
 		fmt.Fprintf(os.Stderr, "  loot [flags]                  Interactive mode\n")
-		fmt.Fprintf(os.Stderr, "  loot [flags] <source> <dest>  CLI mode (positional)\n")
-		fmt.Fprintf(os.Stderr, "  loot --source <src> --dest <dst> [flags]  CLI mode (flags)\n\n")
+		fmt.Fprintf(os.Stderr, "  loot [flags] <source> <dest> [dest...]  CLI mode (positional)\n")
+		fmt.Fprintf(os.Stderr, "  loot --source <src> --dest <dst> [flags]  CLI mode (flags)\n")
+		fmt.Fprintf(os.Stderr, "  loot verify [flags] <dest>    Re-check a destination against its MHL\n\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
 	}
 
 	flag.Parse()
 
+	// Allow flags after positional arguments (loot /src /dst --md5): keep
+	// parsing after each positional so trailing flags aren't taken as
+	// extra destinations.
+	var positional []string
+	for rest := flag.Args(); len(rest) > 0; rest = flag.Args() {
+		positional = append(positional, rest[0])
+		flag.CommandLine.Parse(rest[1:])
+	}
+
 	// Handle version
 	if *versionFlag || *v {
 		fmt.Printf("loot version %s\n", version)
@@ -136,35 +265,51 @@ func ParseFlags(version string) (*Config, error) {
 		cfg.Algorithm = AlgoXXHash64
 	} else {
 		// Fallback to string flag
-		switch algorithmStr {
-		case "xxhash64":
-			cfg.Algorithm = AlgoXXHash64
-		case "md5":
-			cfg.Algorithm = AlgoMD5
-		case "sha256":
-			cfg.Algorithm = AlgoSHA256
-		default:
-			return nil, fmt.Errorf("invalid algorithm: %s (must be xxhash64, md5, or sha256)", algorithmStr)
+		algo, err := ParseAlgorithm(algorithmStr)
+		if err != nil {
+			return nil, err
 		}
+		cfg.Algorithm = algo
+	}
+
+	// Config files and preset fill in whatever wasn't given as a flag:
+	// defaults < user file < project file < preset < flags
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	wd, _ := os.Getwd()
+	file, err := LoadFiles(wd)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := file.Resolve(cfg.Preset)
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.Apply(cfg, explicit); err != nil {
+		return nil, err
+	}
+
+	if cfg.BWLimit < 0 {
+		return nil, fmt.Errorf("invalid --bwlimit %g: expected MB/s >= 0", cfg.BWLimit)
 	}
 
 	// Get positional arguments
-	args := flag.Args()
+	args := positional
 
 	// 1. Check Flags first
-	if cfg.Source != "" && cfg.Destination != "" {
+	if cfg.Source != "" && len(cfg.Destinations) > 0 {
 		cfg.Interactive = false
 	} else if len(args) >= 2 {
 		// 2. Check Positional Args (override flags if provided? or fallback?)
-		// Let's use positional if flags are empty
+		// Let's use positional if flags are empty: <source> <dest> [dest...]
 		if cfg.Source == "" {
 			cfg.Source = args[0]
 		}
-		if cfg.Destination == "" {
-			cfg.Destination = args[1]
+		if len(cfg.Destinations) == 0 {
+			cfg.Destinations = append(cfg.Destinations, args[1:]...)
 		}
 		cfg.Interactive = false
-	} else if cfg.Source != "" || cfg.Destination != "" {
+	} else if cfg.Source != "" || len(cfg.Destinations) > 0 {
 		// Partial flags?
 		return nil, fmt.Errorf("both --source and --dest are required for CLI mode")
 	} else {
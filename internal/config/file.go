@@ -0,0 +1,356 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ProjectConfigFile is the per-project config, looked up in the working
+// directory. It overrides the user-level file.
+const ProjectConfigFile = ".loot.toml"
+
+// Settings are the persistable parts of Config. Unset (nil) fields leave
+// the current value alone, so files and presets only override what they name.
+type Settings struct {
+	Algorithm       *string `toml:"algorithm,omitempty"`
+	DualHash        *bool   `toml:"dual_hash,omitempty"`
+	Concurrency     *int    `toml:"concurrency,omitempty"`
+	BufferSize      *int    `toml:"buffer_size,omitempty"`
+	MetadataMode    *string `toml:"metadata_mode,omitempty"`
+	NoVerify        *bool   `toml:"no_verify,omitempty"`
+	VerifyTimes     *bool   `toml:"verify_times,omitempty"`
+	NoPreserveTimes *bool   `toml:"no_preserve_times,omitempty"`
+	NoPreserveMode  *bool   `toml:"no_preserve_mode,omitempty"`
+	NoXattrs        *bool   `toml:"no_xattrs,omitempty"`
+	IsolateFailures *bool   `toml:"isolate_failures,omitempty"`
+	JobName         *string `toml:"job_name,omitempty"`
+	Camera          *string `toml:"camera,omitempty"`
+	Reel            *string `toml:"reel,omitempty"`
+	ShootDay        *string `toml:"shoot_day,omitempty"`
+	DestTemplate    *string `toml:"dest_template,omitempty"`
+
+	Symlinks          *string   `toml:"symlinks,omitempty"`
+	Include           *[]string `toml:"include,omitempty"`
+	Exclude           *[]string `toml:"exclude,omitempty"`
+	NoDefaultExcludes *bool     `toml:"no_default_excludes,omitempty"`
+
+	BWLimit      *float64            `toml:"bwlimit,omitempty"`
+	DestBWLimits *map[string]float64 `toml:"dest_bwlimits,omitempty"`
+}
+
+// File is a config file: top-level defaults plus named presets
+//
+//	algorithm = "xxhash64"
+//
+//	[presets.ARRI-dailies]
+//	algorithm = "md5"
+//	dual_hash = true
+type File struct {
+	Settings
+	Presets map[string]Settings `toml:"presets,omitempty"`
+}
+
+// UserConfigPath returns the user-level config file
+// (e.g. ~/.config/loot/config.toml on Linux)
+func UserConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "loot", "config.toml"), nil
+}
+
+// ReadFile parses a config file. A missing file yields an empty File.
+func ReadFile(path string) (*File, error) {
+	f := &File{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toml.Decode(string(data), f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// LoadFiles merges the user-level file and the project file in dir
+func LoadFiles(dir string) (*File, error) {
+	merged := &File{}
+
+	if path, err := UserConfigPath(); err == nil {
+		user, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		merged.merge(user)
+	}
+
+	project, err := ReadFile(filepath.Join(dir, ProjectConfigFile))
+	if err != nil {
+		return nil, err
+	}
+	merged.merge(project)
+	return merged, nil
+}
+
+// merge overlays other on f; presets with the same name are merged too
+func (f *File) merge(other *File) {
+	f.Settings.merge(other.Settings)
+	for name, p := range other.Presets {
+		if f.Presets == nil {
+			f.Presets = make(map[string]Settings)
+		}
+		s := f.Presets[name]
+		s.merge(p)
+		f.Presets[name] = s
+	}
+}
+
+// PresetNames lists the presets, sorted
+func (f *File) PresetNames() []string {
+	names := make([]string, 0, len(f.Presets))
+	for name := range f.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the file defaults with the named preset applied on top
+func (f *File) Resolve(preset string) (Settings, error) {
+	s := f.Settings
+	if preset == "" {
+		return s, nil
+	}
+	p, ok := f.Presets[preset]
+	if !ok {
+		if len(f.Presets) == 0 {
+			return s, fmt.Errorf("unknown preset %q (no presets defined)", preset)
+		}
+		return s, fmt.Errorf("unknown preset %q (available: %s)", preset, strings.Join(f.PresetNames(), ", "))
+	}
+	s.merge(p)
+	return s, nil
+}
+
+func (s *Settings) merge(other Settings) {
+	if other.Algorithm != nil {
+		s.Algorithm = other.Algorithm
+	}
+	if other.DualHash != nil {
+		s.DualHash = other.DualHash
+	}
+	if other.Concurrency != nil {
+		s.Concurrency = other.Concurrency
+	}
+	if other.BufferSize != nil {
+		s.BufferSize = other.BufferSize
+	}
+	if other.MetadataMode != nil {
+		s.MetadataMode = other.MetadataMode
+	}
+	if other.NoVerify != nil {
+		s.NoVerify = other.NoVerify
+	}
+	if other.VerifyTimes != nil {
+		s.VerifyTimes = other.VerifyTimes
+	}
+	if other.NoPreserveTimes != nil {
+		s.NoPreserveTimes = other.NoPreserveTimes
+	}
+	if other.NoPreserveMode != nil {
+		s.NoPreserveMode = other.NoPreserveMode
+	}
+	if other.NoXattrs != nil {
+		s.NoXattrs = other.NoXattrs
+	}
+	if other.IsolateFailures != nil {
+		s.IsolateFailures = other.IsolateFailures
+	}
+	if other.JobName != nil {
+		s.JobName = other.JobName
+	}
+	if other.Camera != nil {
+		s.Camera = other.Camera
+	}
+	if other.Reel != nil {
+		s.Reel = other.Reel
+	}
+	if other.ShootDay != nil {
+		s.ShootDay = other.ShootDay
+	}
+	if other.DestTemplate != nil {
+		s.DestTemplate = other.DestTemplate
+	}
+	if other.Symlinks != nil {
+		s.Symlinks = other.Symlinks
+	}
+	if other.Include != nil {
+		s.Include = other.Include
+	}
+	if other.Exclude != nil {
+		s.Exclude = other.Exclude
+	}
+	if other.NoDefaultExcludes != nil {
+		s.NoDefaultExcludes = other.NoDefaultExcludes
+	}
+	if other.BWLimit != nil {
+		s.BWLimit = other.BWLimit
+	}
+	if other.DestBWLimits != nil {
+		s.DestBWLimits = other.DestBWLimits
+	}
+}
+
+// Apply copies the values set in s onto cfg. Settings whose flags are in
+// explicit (given on the command line) are left alone.
+func (s Settings) Apply(cfg *Config, explicit map[string]bool) error {
+	given := func(names ...string) bool {
+		for _, n := range names {
+			if explicit[n] {
+				return true
+			}
+		}
+		return false
+	}
+
+	if s.Algorithm != nil && !given("algorithm", "md5", "sha256", "xxhash64") {
+		algo, err := ParseAlgorithm(*s.Algorithm)
+		if err != nil {
+			return err
+		}
+		cfg.Algorithm = algo
+	}
+	if s.DualHash != nil && !given("dual-hash") {
+		cfg.DualHash = *s.DualHash
+	}
+	if s.Concurrency != nil && !given("concurrency", "c") {
+		cfg.Concurrency = *s.Concurrency
+	}
+	if s.BufferSize != nil && !given("buffer-size") {
+		cfg.BufferSize = *s.BufferSize
+	}
+	if s.MetadataMode != nil && !given("metadata-mode") {
+		cfg.MetadataMode = *s.MetadataMode
+	}
+	if s.NoVerify != nil && !given("no-verify") {
+		cfg.NoVerify = *s.NoVerify
+	}
+	if s.VerifyTimes != nil && !given("verify-times") {
+		cfg.VerifyTimes = *s.VerifyTimes
+	}
+	if s.NoPreserveTimes != nil && !given("no-preserve-times") {
+		cfg.NoPreserveTimes = *s.NoPreserveTimes
+	}
+	if s.NoPreserveMode != nil && !given("no-preserve-mode") {
+		cfg.NoPreserveMode = *s.NoPreserveMode
+	}
+	if s.NoXattrs != nil && !given("no-xattrs") {
+		cfg.NoXattrs = *s.NoXattrs
+	}
+	if s.IsolateFailures != nil && !given("isolate-failures") {
+		cfg.IsolateFailures = *s.IsolateFailures
+	}
+	if s.JobName != nil && !given("job-name") {
+		cfg.JobName = *s.JobName
+	}
+	if s.Camera != nil && !given("camera") {
+		cfg.Camera = *s.Camera
+	}
+	if s.Reel != nil && !given("reel") {
+		cfg.Reel = *s.Reel
+	}
+	if s.ShootDay != nil && !given("day") {
+		cfg.ShootDay = *s.ShootDay
+	}
+	if s.DestTemplate != nil && !given("dest-template") {
+		cfg.DestTemplate = *s.DestTemplate
+	}
+	if s.Symlinks != nil && !given("symlinks") {
+		cfg.Symlinks = *s.Symlinks
+	}
+	if s.Include != nil && !given("include") {
+		cfg.Include = *s.Include
+	}
+	if s.Exclude != nil && !given("exclude") {
+		cfg.Exclude = *s.Exclude
+	}
+	if s.NoDefaultExcludes != nil && !given("no-default-excludes") {
+		cfg.NoDefaultExcludes = *s.NoDefaultExcludes
+	}
+	if s.BWLimit != nil && !given("bwlimit") {
+		cfg.BWLimit = *s.BWLimit
+	}
+	if s.DestBWLimits != nil && !given("dest-bwlimit") {
+		cfg.DestBWLimits = *s.DestBWLimits
+	}
+	return nil
+}
+
+// SettingsFrom captures every persistable value of cfg.
+// The pointers refer to cfg's own fields.
+func SettingsFrom(cfg *Config) Settings {
+	algo := string(cfg.Algorithm)
+	return Settings{
+		Algorithm:       &algo,
+		DualHash:        &cfg.DualHash,
+		Concurrency:     &cfg.Concurrency,
+		BufferSize:      &cfg.BufferSize,
+		MetadataMode:    &cfg.MetadataMode,
+		NoVerify:        &cfg.NoVerify,
+		VerifyTimes:     &cfg.VerifyTimes,
+		NoPreserveTimes: &cfg.NoPreserveTimes,
+		NoPreserveMode:  &cfg.NoPreserveMode,
+		NoXattrs:        &cfg.NoXattrs,
+		IsolateFailures: &cfg.IsolateFailures,
+		JobName:         &cfg.JobName,
+		Camera:          &cfg.Camera,
+		Reel:            &cfg.Reel,
+		ShootDay:        &cfg.ShootDay,
+		DestTemplate:    &cfg.DestTemplate,
+
+		Symlinks:          &cfg.Symlinks,
+		Include:           &cfg.Include,
+		Exclude:           &cfg.Exclude,
+		NoDefaultExcludes: &cfg.NoDefaultExcludes,
+
+		BWLimit:      &cfg.BWLimit,
+		DestBWLimits: &cfg.DestBWLimits,
+	}
+}
+
+// SavePreset stores cfg as a named preset in the config file at path,
+// replacing any preset with that name. Other content is kept, but comments
+// are not preserved.
+func SavePreset(path, name string, cfg *Config) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("preset name is empty")
+	}
+
+	f, err := ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if f.Presets == nil {
+		f.Presets = make(map[string]Settings)
+	}
+	f.Presets[name] = SettingsFrom(cfg)
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(f); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFiles_ProjectAndPresetOverrideUser(t *testing.T) {
+	home, err := ioutil.TempDir("", "loot_cfg_home_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("HOME", home)
+
+	userPath, err := UserConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Dir(userPath), 0755)
+	user := `
+algorithm = "md5"
+concurrency = 8
+camera = "A"
+
+[presets.ARRI-dailies]
+dual_hash = true
+reel = "A001"
+`
+	if err := ioutil.WriteFile(userPath, []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := ioutil.TempDir("", "loot_cfg_project_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(project)
+	projectCfg := `
+concurrency = 2
+
+[presets.ARRI-dailies]
+metadata_mode = "header"
+`
+	if err := ioutil.WriteFile(filepath.Join(project, ProjectConfigFile), []byte(projectCfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadFiles(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := f.Resolve("ARRI-dailies")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	// Flags given on the command line always win
+	if err := s.Apply(cfg, map[string]bool{"camera": true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Algorithm != AlgoMD5 {
+		t.Errorf("Algorithm = %s, want md5 from user file", cfg.Algorithm)
+	}
+	if cfg.Concurrency != 2 {
+		t.Errorf("Concurrency = %d, want 2 from project file", cfg.Concurrency)
+	}
+	if !cfg.DualHash || cfg.Reel != "A001" || cfg.MetadataMode != "header" {
+		t.Errorf("preset not applied: dual=%v reel=%q mode=%q", cfg.DualHash, cfg.Reel, cfg.MetadataMode)
+	}
+	if cfg.Camera != "" {
+		t.Errorf("Camera = %q, explicit flag should have kept the default", cfg.Camera)
+	}
+
+	if _, err := f.Resolve("RED-archive"); err == nil {
+		t.Error("unknown preset should be an error")
+	}
+}
+
+func TestSavePreset_RoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_cfg_save_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "loot", "config.toml")
+
+	cfg := DefaultConfig()
+	cfg.Algorithm = AlgoSHA256
+	cfg.Camera = "B"
+	cfg.BWLimit = 120
+	cfg.DestBWLimits = map[string]float64{"/mnt/nas": 40}
+	if err := SavePreset(path, "RED-archive", cfg); err != nil {
+		t.Fatal(err)
+	}
+	// Saving another preset keeps the first one
+	if err := SavePreset(path, "ARRI-dailies", DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Presets) != 2 {
+		t.Fatalf("presets = %v, want 2", f.PresetNames())
+	}
+	s, err := f.Resolve("RED-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := DefaultConfig()
+	if err := s.Apply(loaded, nil); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Algorithm != AlgoSHA256 || loaded.Camera != "B" {
+		t.Errorf("loaded preset: algorithm=%s camera=%q", loaded.Algorithm, loaded.Camera)
+	}
+	if loaded.BWLimit != 120 || loaded.DestBWLimits["/mnt/nas"] != 40 {
+		t.Errorf("loaded preset: bwlimit=%g dest_bwlimits=%v", loaded.BWLimit, loaded.DestBWLimits)
+	}
+}
+
+func TestLimitMapFlag(t *testing.T) {
+	var limits map[string]float64
+	m := limitMap{&limits}
+	for _, v := range []string{"/mnt/nas=80", "/Volumes/A=B=12.5"} {
+		if err := m.Set(v); err != nil {
+			t.Fatalf("Set(%q): %v", v, err)
+		}
+	}
+	if limits["/mnt/nas"] != 80 || limits["/Volumes/A=B"] != 12.5 {
+		t.Errorf("limits = %v", limits)
+	}
+	for _, bad := range []string{"/mnt/nas", "=80", "/mnt/nas=fast", "/mnt/nas=-1"} {
+		if err := m.Set(bad); err == nil {
+			t.Errorf("Set(%q) should fail", bad)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"hash"
 	"io"
 	"os"
+	"strings"
 
 	"loot/internal/config"
 
@@ -76,7 +77,7 @@ func (mh *MultiHasher) Sum() HashResult {
 	result := HashResult{}
 
 	if mh.xxh != nil {
-		result.XXHash64 = fmt.Sprintf("%x", mh.xxh.Sum64())
+		result.XXHash64 = fmt.Sprintf("%016x", mh.xxh.Sum64())
 	}
 	if mh.md5Hash != nil {
 		result.MD5 = hex.EncodeToString(mh.md5Hash.Sum(nil))
@@ -117,6 +118,26 @@ func (result HashResult) String() string {
 	return s
 }
 
+// Compare checks the algorithms present in both results. comparable is
+// false when they have no algorithm in common.
+func (result HashResult) Compare(other HashResult) (match, comparable bool) {
+	pairs := [][2]string{
+		{result.XXHash64, other.XXHash64},
+		{result.MD5, other.MD5},
+		{result.SHA256, other.SHA256},
+	}
+	for _, p := range pairs {
+		if p[0] == "" || p[1] == "" {
+			continue
+		}
+		comparable = true
+		if !strings.EqualFold(p[0], p[1]) {
+			return false, true
+		}
+	}
+	return comparable, comparable
+}
+
 // CalculateFileHash calculates hash(es) for a file
 func CalculateFileHash(path string, algorithms ...config.HashAlgorithm) (HashResult, error) {
 	f, err := os.Open(path)
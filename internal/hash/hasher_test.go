@@ -74,3 +74,26 @@ func TestCalculateFileHash(t *testing.T) {
 		t.Errorf("File hash mismatch. Got %s, want %s", res.MD5, expectedMD5)
 	}
 }
+
+func TestHashResultCompare(t *testing.T) {
+	a := HashResult{XXHash64: "00000000deadbeef", MD5: "098f6bcd4621d373cade4e832627b4f6"}
+
+	tests := []struct {
+		name       string
+		other      HashResult
+		match      bool
+		comparable bool
+	}{
+		{"same", a, true, true},
+		{"common subset", HashResult{MD5: "098F6BCD4621D373CADE4E832627B4F6"}, true, true},
+		{"differs", HashResult{XXHash64: "00000000deadbeee"}, false, true},
+		{"nothing in common", HashResult{SHA256: "abc"}, false, false},
+		{"empty", HashResult{}, false, false},
+	}
+	for _, tt := range tests {
+		match, comparable := a.Compare(tt.other)
+		if match != tt.match || comparable != tt.comparable {
+			t.Errorf("%s: Compare = %v, %v; want %v, %v", tt.name, match, comparable, tt.match, tt.comparable)
+		}
+	}
+}
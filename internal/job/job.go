@@ -2,10 +2,15 @@ package job
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"loot/internal/config"
+	"loot/internal/hash"
 	"loot/internal/mhl"
 	"loot/internal/offload"
 	"loot/internal/output"
@@ -22,6 +27,10 @@ const (
 	StatusCompleted Status = "Completed"
 	StatusFailed    Status = "Failed"
 	StatusCancelled Status = "Cancelled"
+	StatusPaused    Status = "Paused"
+
+	// StatusInterrupted is a job restored from a journal that never finished
+	StatusInterrupted Status = "Interrupted"
 )
 
 // Msg is sent via channel to UI
@@ -56,6 +65,16 @@ type Job struct {
 	// Result
 	Result *output.JobResult
 	Err    error
+
+	// Journal persists progress while the job runs (nil: not journaled)
+	Journal *Journal
+
+	// Files completed by an earlier, interrupted run of this job
+	resumeFiles map[string]JournalFile
+
+	// Stage to go back to when a paused job continues
+	pauseMu    sync.Mutex
+	pausedFrom Status
 }
 
 func NewJob(cfg *config.Config) *Job {
@@ -63,17 +82,133 @@ func NewJob(cfg *config.Config) *Job {
 	return &Job{
 		ID:        fmt.Sprintf("job-%d", time.Now().UnixNano()), // Use Nano for better uniqueness
 		Config:    cfg,
-		Offloader: offload.NewOffloaderWithConfig(cfg, cfg.Source, cfg.Destination),
+		Offloader: offload.NewOffloaderWithConfig(cfg, cfg.Source, cfg.Destinations...),
 		Status:    StatusPending,
 		ctx:       ctx,
 		cancel:    cancel,
 	}
 }
 
+// RestoreJob rebuilds a job from its journal. Finished jobs come back as
+// history, unfinished ones as StatusInterrupted, ready to be resumed.
+func RestoreJob(e *JournalEntry) *Job {
+	j := NewJob(e.Config)
+	j.ID = e.ID
+	j.Status = e.Status
+	j.StartTime = e.StartTime
+	j.EndTime = e.UpdatedAt
+	j.resumeFiles = e.Files
+	if !e.Finished() {
+		j.Status = StatusInterrupted
+	}
+	if e.Error != "" {
+		j.Err = errors.New(e.Error)
+	}
+	return j
+}
+
+// Resume returns a new run of this job in resume mode, keeping its ID (and
+// so its journal). Files verified by earlier runs are trusted by hash on
+// the destinations they were verified on.
+func (j *Job) Resume() *Job {
+	cfg := *j.Config
+	cfg.SkipExisting = true
+	cfg.BWLimit = j.Offloader.BandwidthLimit() // Keep a limit changed while running
+
+	nj := NewJob(&cfg)
+	nj.ID = j.ID
+	nj.resumeFiles = j.resumeFiles
+	if nj.resumeFiles == nil {
+		nj.resumeFiles = make(map[string]JournalFile)
+	}
+	for _, f := range j.Offloader.Files {
+		if f.Hash != (hash.HashResult{}) {
+			path := filepath.ToSlash(f.RelPath)
+			nj.resumeFiles[path] = JournalFile{RelPath: path, Size: f.Size, Hash: f.Hash, Verified: verifiedOn(f)}
+		}
+	}
+	return nj
+}
+
+// SetBandwidthLimit changes the job's global bandwidth limit (MB/s, 0 for
+// unlimited). Safe to call while the job runs.
+func (j *Job) SetBandwidthLimit(mbps float64) {
+	j.Offloader.SetBandwidthLimit(mbps)
+}
+
+// BandwidthLimit returns the job's global bandwidth limit in MB/s, 0 if unlimited
+func (j *Job) BandwidthLimit() float64 {
+	return j.Offloader.BandwidthLimit()
+}
+
+// Pause holds a copying or verifying job between buffers. Files stay open
+// and the job continues from the same offset on Unpause. It returns false
+// if the job isn't in a stage that can be paused.
+func (j *Job) Pause() bool {
+	j.pauseMu.Lock()
+	defer j.pauseMu.Unlock()
+	if j.Status != StatusCopying && j.Status != StatusVerifying {
+		return false
+	}
+	if !j.Offloader.Pause() {
+		return false
+	}
+	j.pausedFrom = j.Status
+	j.Status = StatusPaused
+	j.journalStatus(j.Status, nil)
+	return true
+}
+
+// Unpause continues a paused job. It returns false if the job isn't paused.
+func (j *Job) Unpause() bool {
+	j.pauseMu.Lock()
+	defer j.pauseMu.Unlock()
+	if j.Status != StatusPaused {
+		return false
+	}
+	j.Status = j.pausedFrom
+	j.journalStatus(j.Status, nil)
+	j.Offloader.Unpause()
+	return true
+}
+
+// enterStage moves the job to a new stage. A paused job stays paused and
+// enters the stage when it continues.
+func (j *Job) enterStage(stage Status) {
+	j.pauseMu.Lock()
+	defer j.pauseMu.Unlock()
+	if j.Status == StatusPaused {
+		j.pausedFrom = stage
+		return
+	}
+	j.Status = stage
+	j.journalStatus(j.Status, nil)
+}
+
+// setStatus moves the job to a status that ends or restarts its stages
+// (running, completed, failed, cancelled). A pause still pending is
+// dropped along with it: there is nothing left to hold.
+func (j *Job) setStatus(status Status) {
+	j.pauseMu.Lock()
+	defer j.pauseMu.Unlock()
+	if j.Status == StatusPaused {
+		j.Offloader.Unpause()
+	}
+	j.Status = status
+}
+
+// State returns the current status. Status is written under pauseMu while
+// the job runs, so other goroutines read it through State.
+func (j *Job) State() Status {
+	j.pauseMu.Lock()
+	defer j.pauseMu.Unlock()
+	return j.Status
+}
+
 func (j *Job) Cancel() {
 	if j.cancel != nil {
 		j.cancel()
-		j.Status = StatusCancelled
+		j.setStatus(StatusCancelled)
 	}
 }
 
@@ -82,7 +217,7 @@ func (j *Job) Cancel() {
 // For TUI, we might want a channel to report progress.
 func (j *Job) Run(updates chan Msg) {
 	j.StartTime = time.Now()
-	j.Status = StatusRunning
+	j.setStatus(StatusRunning)
 
 	// Check cancellation before start
 	if j.ctx.Err() != nil {
@@ -90,44 +225,32 @@ func (j *Job) Run(updates chan Msg) {
 		return
 	}
 
-	// 1. COPY
-	j.Status = StatusCopying
-	updates <- Msg{Job: j, Stage: StatusCopying, Status: "Copying...", JobChannel: updates}
-
-	progressCh := make(chan offload.ProgressInfo, 100)
-	errCh := make(chan error, 1)
+	// 0. PRE-FLIGHT: the source must be complete and fit every destination
+	if err := j.preflight(); err != nil {
+		j.fail(err, updates)
+		return
+	}
 
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				errCh <- fmt.Errorf("panic: %v", r)
-			}
-			close(progressCh)
-		}()
-		errCh <- j.Offloader.Copy(j.ctx, progressCh)
-	}()
+	if j.Journal != nil {
+		j.Journal.Start(j)
+		j.Offloader.OnFile = func(f offload.FileRes) { j.Journal.File(f) }
+	}
 
-	// Consume progress
-loop:
-	for {
-		select {
-		case <-j.ctx.Done():
-			// Cancelled
-			j.fail(j.ctx.Err(), updates)
-			return
-		case info, ok := <-progressCh:
-			if !ok {
-				break loop
-			}
-			j.TotalBytes = info.TotalBytes
-			j.CopiedBytes = info.CopiedBytes
-			j.Speed = info.Speed
-			updates <- Msg{Job: j, Progress: info, Stage: StatusCopying, Status: "Copying...", JobChannel: updates}
-		}
+	// Resume: hashes from the previous job's MHLs (and from an interrupted
+	// run's journal) let intact copies be skipped without reading them back
+	if j.Config.SkipExisting {
+		j.Offloader.PreviousHashes = previousHashes(j.Offloader.Destinations)
+		previousFromJournal(j.Offloader.PreviousHashes, j.Offloader.Destinations, j.resumeFiles)
 	}
 
-	// Check copy error
-	if err := <-errCh; err != nil {
+	// 1. COPY
+	j.enterStage(StatusCopying)
+	updates <- Msg{Job: j, Stage: StatusCopying, Status: "Copying...", JobChannel: updates}
+
+	err := j.runStage(StatusCopying, "Copying...", updates, func(progressCh chan<- offload.ProgressInfo) error {
+		return j.Offloader.Copy(j.ctx, progressCh)
+	})
+	if err != nil {
 		j.fail(err, updates)
 		return
 	}
@@ -140,24 +263,41 @@ loop:
 
 	// 2. VERIFY
 	if !j.Config.NoVerify {
-		j.Status = StatusVerifying
+		j.enterStage(StatusVerifying)
 		updates <- Msg{Job: j, Stage: StatusVerifying, Status: "Verifying...", JobChannel: updates}
 
-		// TODO: Pass context to Verify
-		success, err := j.Offloader.Verify()
+		var success bool
+		err = j.runStage(StatusVerifying, "Verifying...", updates, func(progressCh chan<- offload.ProgressInfo) error {
+			ok, verifyErr := j.Offloader.Verify(j.ctx, progressCh)
+			success = ok
+			return verifyErr
+		})
+		if j.Journal != nil {
+			// Only copies read back intact are trusted by a later resume
+			for _, f := range j.Offloader.Files {
+				j.Journal.Verified(f)
+			}
+		}
+		if j.ctx.Err() != nil {
+			j.fail(j.ctx.Err(), updates)
+			return
+		}
 		if err != nil {
 			j.fail(fmt.Errorf("verification error: %w", err), updates)
 			return
 		}
 		if !success {
-			j.fail(fmt.Errorf("checksum mismatch"), updates)
+			// Reports still list every file with its per-destination outcome
+			j.EndTime = time.Now()
+			j.generateReports()
+			j.fail(verifyError(j.Offloader.VerifyFailures()), updates)
 			return
 		}
 	}
 
 	// 3. COMPLETE & REPORT
 	j.EndTime = time.Now()
-	j.Status = StatusCompleted
+	j.setStatus(StatusCompleted)
 	j.Err = nil
 
 	// Generate reports
@@ -165,32 +305,173 @@ loop:
 
 	// Create Result
 	j.Result = j.createResult()
+	j.journalStatus(StatusCompleted, nil)
 
-	updates <- Msg{Job: j, Stage: StatusCompleted, Status: "Done!", Finished: true, JobChannel: updates}
+	status := "Done!"
+	if degraded := j.Offloader.Degraded(); len(degraded) > 0 {
+		status = fmt.Sprintf("Done, %d destination(s) degraded", len(degraded))
+	}
+	updates <- Msg{Job: j, Stage: StatusCompleted, Status: status, Finished: true, JobChannel: updates}
+}
+
+// runStage runs fn in the background and forwards its progress to updates
+// until it finishes or the job is cancelled. Only the copy stage feeds the
+// job's byte counters, so verify reads don't inflate the transfer stats.
+func (j *Job) runStage(stage Status, status string, updates chan Msg, fn func(chan<- offload.ProgressInfo) error) error {
+	progressCh := make(chan offload.ProgressInfo, 100)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("panic: %v", r)
+			}
+			close(progressCh)
+		}()
+		errCh <- fn(progressCh)
+	}()
+
+	// Consume progress
+	for {
+		select {
+		case <-j.ctx.Done():
+			// Cancelled
+			return j.ctx.Err()
+		case info, ok := <-progressCh:
+			if !ok {
+				return <-errCh
+			}
+			if stage == StatusCopying {
+				j.TotalBytes = info.TotalBytes
+				j.CopiedBytes = info.CopiedBytes
+				j.Speed = info.Speed
+			}
+			updates <- Msg{Job: j, Progress: info, Stage: stage, Status: status, JobChannel: updates}
+		}
+	}
+}
+
+// previousHashes loads the hash lists left on each destination by earlier jobs.
+// Destinations without a usable MHL are left out; their copies get re-hashed.
+func previousHashes(dests []string) map[string]map[string]hash.HashResult {
+	prev := make(map[string]map[string]hash.HashResult)
+	for _, dst := range dests {
+		m, err := mhl.LoadManifest(dst, "")
+		if err != nil {
+			continue
+		}
+		hashes := make(map[string]hash.HashResult, len(m.Entries))
+		for path, e := range m.Entries {
+			if e.Link == "" {
+				hashes[path] = e.Hash
+			}
+		}
+		prev[dst] = hashes
+	}
+	return prev
+}
+
+// preflight uses the dry run to refuse incomplete cards (unless
+// IgnoreStructure) and destinations that can't fit the source. On resume,
+// part of the data is already there so the space check is skipped.
+func (j *Job) preflight() error {
+	res, err := j.Offloader.DryRun()
+	if err != nil {
+		return err
+	}
+	if len(res.Issues) > 0 && !j.Config.IgnoreStructure {
+		return fmt.Errorf("source is incomplete, %d issue(s), first: %s (use --ignore-structure to copy anyway)",
+			len(res.Issues), res.Issues[0])
+	}
+	if j.Config.SkipExisting {
+		return nil
+	}
+	for _, d := range res.Destinations {
+		// An unknown free space doesn't block: the copy reports a real shortage
+		if d.SpaceKnown && !d.CanFit {
+			return fmt.Errorf("insufficient space on %s: need %s, %s free",
+				d.Path, offload.FormatBytes(uint64(res.TotalSize)), offload.FormatBytes(d.FreeSpace))
+		}
+	}
+	return nil
 }
 
 func (j *Job) fail(err error, updates chan Msg) {
 	j.EndTime = time.Now()
-	j.Status = StatusFailed
+	j.setStatus(StatusFailed)
 	j.Err = err
 	j.Result = j.createResult() // Create result even on failure
+	j.journalStatus(StatusFailed, err)
 	updates <- Msg{Job: j, Stage: StatusFailed, Status: fmt.Sprintf("Failed: %v", err), Err: err, Finished: true, JobChannel: updates}
 }
 
+// journalStatus records status, if the job is journaled
+func (j *Job) journalStatus(status Status, err error) {
+	if j.Journal != nil {
+		j.Journal.Status(status, err)
+	}
+}
+
+// verifyError summarises verification failures by outcome
+func verifyError(failed []offload.FileRes) error {
+	counts := map[offload.VerifyStatus]int{}
+	for _, f := range failed {
+		for _, v := range f.Verify {
+			if v.Status != offload.VerifyOK {
+				counts[v.Status]++
+			}
+		}
+	}
+	msg := fmt.Sprintf("verification failed for %d file(s): %d mismatch, %d missing, %d unreadable",
+		len(failed), counts[offload.VerifyMismatch], counts[offload.VerifyMissing], counts[offload.VerifyUnreadable])
+	if n := counts[offload.VerifyTimeChanged]; n > 0 {
+		msg += fmt.Sprintf(", %d modification time changed", n)
+	}
+	return errors.New(msg)
+}
+
 func (j *Job) generateReports() {
 	for _, dst := range j.Offloader.Destinations {
 		// PDF
 		reportPath := dst + ".pdf"
-		if err := report.GeneratePDF(reportPath, j.Offloader, j.StartTime, j.EndTime); err != nil {
+		if err := report.GeneratePDF(reportPath, j.Offloader, dst, j.StartTime, j.EndTime); err != nil {
 			// Log warning?
 		}
 
+		// A dropped destination only holds part of the data: no hash lists
+		if j.Offloader.IsDegraded(dst) {
+			continue
+		}
+
+		// Hash lists only vouch for files that are intact on this destination
+		intact := filesIntactAt(j.Offloader.Files, dst)
+
 		// MHL
 		mhlPath := dst + ".mhl"
-		if err := mhl.GenerateMHL(mhlPath, j.Offloader.Files); err != nil {
+		if err := mhl.GenerateMHL(mhlPath, intact); err != nil {
 			// Log warning?
 		}
+
+		// ASC MHL (appends a new generation inside the destination)
+		if info, err := os.Stat(dst); err == nil && info.IsDir() {
+			if err := mhl.GenerateASCMHL(dst, intact, j.Config); err != nil {
+				// Log warning?
+			}
+		}
+	}
+}
+
+// filesIntactAt drops files that failed verification on dst.
+// Files without a verification outcome (--no-verify) are kept.
+func filesIntactAt(files []offload.FileRes, dst string) []offload.FileRes {
+	intact := make([]offload.FileRes, 0, len(files))
+	for _, f := range files {
+		if status, ok := f.StatusFor(dst); ok && status != offload.VerifyOK {
+			continue
+		}
+		intact = append(intact, f)
 	}
+	return intact
 }
 
 func (j *Job) createResult() *output.JobResult {
@@ -200,6 +481,8 @@ func (j *Job) createResult() *output.JobResult {
 		speed = float64(j.CopiedBytes) / 1024 / 1024 / duration.Seconds()
 	}
 
+	degraded := j.Offloader.Degraded()
+
 	statusStr := "success"
 	errStr := ""
 	if j.Err != nil {
@@ -207,17 +490,67 @@ func (j *Job) createResult() *output.JobResult {
 		errStr = j.Err.Error()
 	}
 
+	var dests []output.DestinationResult
+	for _, dst := range j.Offloader.Destinations {
+		dr := output.DestinationResult{
+			Path:   dst,
+			Status: statusStr,
+			Report: dst + ".pdf",
+			MHL:    dst + ".mhl",
+
+			BWLimitMBps: j.Offloader.DestBandwidthLimit(dst),
+		}
+		if failure, ok := findFailure(degraded, dst); ok {
+			dr.Status = "degraded"
+			dr.FailedAt = failure.File
+			dr.Error = failure.Error
+			dr.MHL = ""
+			dests = append(dests, dr)
+			continue
+		}
+		for _, f := range j.Offloader.Files {
+			if status, ok := f.StatusFor(dst); ok && status != offload.VerifyOK {
+				dr.FailedFiles++
+			}
+		}
+		if dr.FailedFiles > 0 {
+			dr.Status = "failed"
+		}
+		dests = append(dests, dr)
+	}
+
+	// The job went through, but not on every destination
+	if statusStr == "success" && len(degraded) > 0 {
+		statusStr = "degraded"
+	}
+
+	clips, files := offload.GroupClips(j.Offloader.Files)
+
 	return &output.JobResult{
-		Timestamp:    time.Now(),
-		Source:       j.Offloader.Source,
-		Destinations: j.Offloader.Destinations,
-		Status:       statusStr,
-		TotalFiles:   len(j.Offloader.Files),
-		TotalBytes:   j.TotalBytes,
-		Duration:     duration.String(),
-		DurationMs:   duration.Milliseconds(),
-		SpeedMBps:    speed,
-		Files:        j.Offloader.Files,
-		Error:        errStr,
+		Timestamp:          time.Now(),
+		Source:             j.Offloader.Source,
+		Destinations:       j.Offloader.Destinations,
+		DestinationResults: dests,
+		Status:             statusStr,
+		TotalFiles:         len(j.Offloader.Files),
+		FailedFiles:        len(j.Offloader.VerifyFailures()),
+		TotalBytes:         j.TotalBytes,
+		Duration:           duration.String(),
+		DurationMs:         duration.Milliseconds(),
+		SpeedMBps:          speed,
+		BWLimitMBps:        j.Offloader.BandwidthLimit(),
+		Files:              files,
+		Clips:              clips,
+		Excluded:           j.Offloader.Excluded,
+		Error:              errStr,
+	}
+}
+
+func findFailure(failures []offload.DestFailure, dst string) (offload.DestFailure, bool) {
+	for _, f := range failures {
+		if f.Destination == dst {
+			return f, true
+		}
 	}
+	return offload.DestFailure{}, false
 }
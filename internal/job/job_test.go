@@ -0,0 +1,100 @@
+package job
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"loot/internal/config"
+	"loot/internal/hash"
+	"loot/internal/mhl"
+	"loot/internal/offload"
+)
+
+func TestJob_BlocksIncompleteCard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_job_card_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Sony clip without its M01.XML sidecar
+	clips := filepath.Join(dir, "card", "XDROOT", "Clip")
+	if err := os.MkdirAll(clips, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(clips, "C005C001_240307.MXF"), []byte("mxf"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Source = filepath.Join(dir, "card")
+	cfg.Destinations = []string{filepath.Join(dir, "raid")}
+	cfg.MetadataMode = "off"
+
+	j := NewJob(cfg)
+	j.Run(make(chan Msg, 1000))
+	if j.Status != StatusFailed || j.Err == nil || !strings.Contains(j.Err.Error(), "incomplete") {
+		t.Fatalf("job status = %s (%v), want failed on the structure check", j.Status, j.Err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "raid", "XDROOT")); err == nil {
+		t.Error("blocked job should not have copied anything")
+	}
+
+	override := *cfg
+	override.IgnoreStructure = true
+	j = NewJob(&override)
+	j.Run(make(chan Msg, 1000))
+	if j.Status != StatusCompleted {
+		t.Errorf("job status = %s (%v), want completed with --ignore-structure", j.Status, j.Err)
+	}
+}
+
+func TestJob_HashListsSkipFilesFailedOnDestination(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_job_mhl_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	raid, shuttle := filepath.Join(dir, "raid"), filepath.Join(dir, "shuttle")
+	for _, d := range []string{raid, shuttle} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := config.DefaultConfig()
+	cfg.Source = filepath.Join(dir, "card")
+	cfg.Destinations = []string{raid, shuttle}
+
+	j := NewJob(cfg)
+	j.Offloader.Files = []offload.FileRes{
+		{RelPath: "a.mov", Size: 1, Hash: hash.HashResult{XXHash64: "00000000000000aa"},
+			Verify: []offload.DestVerify{{Destination: raid, Status: offload.VerifyOK}, {Destination: shuttle, Status: offload.VerifyOK}}},
+		{RelPath: "b.mov", Size: 1, Hash: hash.HashResult{XXHash64: "00000000000000bb"},
+			Verify: []offload.DestVerify{{Destination: raid, Status: offload.VerifyOK}, {Destination: shuttle, Status: offload.VerifyMismatch}}},
+	}
+	j.generateReports()
+
+	for dst, want := range map[string]int{raid: 2, shuttle: 1} {
+		ascDir := filepath.Join(dst, mhl.ASCMHLFolder)
+		chain, err := mhl.ReadASCChain(ascDir)
+		if err != nil || len(chain.HashLists) != 1 {
+			t.Fatalf("%s: chain %+v (%v), want one generation", dst, chain, err)
+		}
+		list, err := mhl.ReadASCHashList(filepath.Join(ascDir, chain.HashLists[0].Path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list.Hashes) != want {
+			t.Errorf("%s: ASC MHL lists %d files, want %d", dst, len(list.Hashes), want)
+		}
+		for _, h := range list.Hashes {
+			if dst == shuttle && h.Path.Value == "b.mov" {
+				t.Errorf("%s: ASC MHL lists b.mov, which failed verification there", dst)
+			}
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package job
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"loot/internal/config"
+	"loot/internal/hash"
+	"loot/internal/offload"
+)
+
+// journalExt is the extension of journal files (one JSON record per line)
+const journalExt = ".jsonl"
+
+// journalRetention is how long finished jobs are kept as history
+const journalRetention = 30 * 24 * time.Hour
+
+// Journal is an append-only on-disk log of a job: its config, every file
+// as it completes and each status change. A crash can at worst cut off the
+// last line, which is ignored when reading back.
+type Journal struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// journalRecord is one line of a journal
+type journalRecord struct {
+	Time   time.Time      `json:"time"`
+	Job    *journalHeader `json:"job,omitempty"`
+	File   *JournalFile   `json:"file,omitempty"`
+	Status Status         `json:"status,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+type journalHeader struct {
+	ID     string        `json:"id"`
+	Config config.Config `json:"config"`
+}
+
+// JournalFile is a file that was fully copied, with its source hash.
+// Verified lists the destinations whose copy was read back and matched it;
+// a later copy record for the same file clears it.
+type JournalFile struct {
+	RelPath  string          `json:"path"`
+	Size     int64           `json:"size"`
+	Hash     hash.HashResult `json:"hash"`
+	Verified []string        `json:"verified,omitempty"`
+}
+
+// JournalEntry is a job as reconstructed from its journal
+type JournalEntry struct {
+	Path      string
+	ID        string
+	Config    *config.Config
+	Status    Status
+	Error     string
+	StartTime time.Time
+	UpdatedAt time.Time
+	Files     map[string]JournalFile // Relative, slash separated
+}
+
+// Finished reports whether the job reached a final state. Anything else
+// was interrupted (crash, reboot, quit) and can be resumed.
+func (e *JournalEntry) Finished() bool {
+	switch e.Status {
+	case StatusCompleted, StatusFailed, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// JournalDir returns the default journal location under the user config dir
+func JournalDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "loot", "journal"), nil
+}
+
+// OpenJournal opens (or creates) the journal of job id in dir
+func OpenJournal(dir, id string) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal dir: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, id+journalExt), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	return &Journal{f: f}, nil
+}
+
+// Start records the job and its config
+func (jl *Journal) Start(j *Job) error {
+	return jl.write(journalRecord{Job: &journalHeader{ID: j.ID, Config: *j.Config}, Status: StatusRunning})
+}
+
+// File records a completed file
+func (jl *Journal) File(f offload.FileRes) error {
+	return jl.write(journalRecord{File: &JournalFile{RelPath: filepath.ToSlash(f.RelPath), Size: f.Size, Hash: f.Hash}})
+}
+
+// Verified records the destinations a file was verified on, if any
+func (jl *Journal) Verified(f offload.FileRes) error {
+	dests := verifiedOn(f)
+	if len(dests) == 0 {
+		return nil
+	}
+	return jl.write(journalRecord{File: &JournalFile{RelPath: filepath.ToSlash(f.RelPath), Size: f.Size, Hash: f.Hash, Verified: dests}})
+}
+
+// verifiedOn returns the destinations where f verified OK
+func verifiedOn(f offload.FileRes) []string {
+	var dests []string
+	for _, v := range f.Verify {
+		if v.Status == offload.VerifyOK {
+			dests = append(dests, v.Destination)
+		}
+	}
+	return dests
+}
+
+// Status records a status change, with the error for failed jobs
+func (jl *Journal) Status(status Status, err error) error {
+	rec := journalRecord{Status: status}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	return jl.write(rec)
+}
+
+// Close closes the journal file
+func (jl *Journal) Close() error {
+	jl.mu.Lock()
+	defer jl.mu.Unlock()
+	return jl.f.Close()
+}
+
+func (jl *Journal) write(rec journalRecord) error {
+	rec.Time = time.Now()
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	jl.mu.Lock()
+	defer jl.mu.Unlock()
+	if _, err := jl.f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return jl.f.Sync()
+}
+
+// ReadJournal rebuilds a job from its journal
+func ReadJournal(path string) (*JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	e := &JournalEntry{Path: path, Files: make(map[string]JournalFile)}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var rec journalRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			// Torn write at crash time
+			continue
+		}
+		if rec.Job != nil {
+			cfg := rec.Job.Config
+			e.ID, e.Config = rec.Job.ID, &cfg
+			if e.StartTime.IsZero() {
+				e.StartTime = rec.Time
+			}
+		}
+		if rec.File != nil {
+			e.Files[rec.File.RelPath] = *rec.File
+		}
+		if rec.Status != "" {
+			e.Status, e.Error = rec.Status, rec.Error
+		}
+		e.UpdatedAt = rec.Time
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if e.Config == nil {
+		return nil, errors.New("journal has no job header")
+	}
+	return e, nil
+}
+
+// LoadJournals reads every journal in dir, oldest first. Finished jobs past
+// the retention period are deleted; unreadable journals are skipped.
+func LoadJournals(dir string) ([]*JournalEntry, error) {
+	names, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*JournalEntry
+	for _, n := range names {
+		if n.IsDir() || !strings.HasSuffix(n.Name(), journalExt) {
+			continue
+		}
+		path := filepath.Join(dir, n.Name())
+		e, err := ReadJournal(path)
+		if err != nil {
+			continue
+		}
+		if e.Finished() && time.Since(e.UpdatedAt) > journalRetention {
+			os.Remove(path)
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, k int) bool { return entries[i].StartTime.Before(entries[k].StartTime) })
+	return entries, nil
+}
+
+// previousFromJournal turns the files verified before an interruption into
+// recorded hashes for the destinations they were verified on, so resume can
+// trust them. Copies that were never read back are re-hashed by resume.
+func previousFromJournal(prev map[string]map[string]hash.HashResult, dests []string, files map[string]JournalFile) {
+	for _, dst := range dests {
+		for path, f := range files {
+			if !contains(f.Verified, dst) {
+				continue
+			}
+			if prev[dst] == nil {
+				prev[dst] = make(map[string]hash.HashResult, len(files))
+			}
+			// A hash list written by a finished job wins
+			if _, ok := prev[dst][path]; !ok {
+				prev[dst][path] = f.Hash
+			}
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
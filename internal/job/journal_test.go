@@ -0,0 +1,142 @@
+package job
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"loot/internal/config"
+	"loot/internal/hash"
+	"loot/internal/offload"
+)
+
+func TestJournal_InterruptedJobResumes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_journal_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := config.DefaultConfig()
+	cfg.Source = "/card"
+	cfg.Destinations = []string{"/raid", "/shuttle"}
+	j := NewJob(cfg)
+
+	jl, err := OpenJournal(dir, j.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jl.Start(j)
+	jl.Status(StatusCopying, nil)
+	jl.File(offload.FileRes{RelPath: "A001/clip.mov", Size: 4, Hash: hash.HashResult{XXHash64: "00000000deadbeef"}})
+	jl.File(offload.FileRes{RelPath: "A001/clip2.mov", Size: 4, Hash: hash.HashResult{XXHash64: "00000000cafebabe"}})
+	// Only clip.mov was read back, and only on /raid
+	jl.Verified(offload.FileRes{RelPath: "A001/clip.mov", Size: 4, Hash: hash.HashResult{XXHash64: "00000000deadbeef"},
+		Verify: []offload.DestVerify{{Destination: "/raid", Status: offload.VerifyOK}, {Destination: "/shuttle", Status: offload.VerifyMismatch}}})
+	jl.Close()
+
+	// Crash mid-write: the torn last line must be ignored
+	f, err := os.OpenFile(filepath.Join(dir, j.ID+journalExt), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2024-01-01T00:00:00Z","file":{"pa`)
+	f.Close()
+
+	q := NewQueue()
+	q.JournalDir = dir
+	n, err := q.Restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(q.Failed) != 1 {
+		t.Fatalf("Restore: %d interrupted, %d failed; want 1, 1", n, len(q.Failed))
+	}
+	restored := q.Failed[0]
+	if restored.ID != j.ID || restored.Status != StatusInterrupted {
+		t.Errorf("restored job = %s (%s), want %s (%s)", restored.ID, restored.Status, j.ID, StatusInterrupted)
+	}
+	if len(restored.Config.Destinations) != 2 {
+		t.Errorf("config not restored: %+v", restored.Config.Destinations)
+	}
+
+	resumed, ok := q.Resume(j.ID)
+	if !ok {
+		t.Fatal("Resume did not find the interrupted job")
+	}
+	if resumed.ID != j.ID || !resumed.Config.SkipExisting {
+		t.Errorf("resumed job should keep the ID and enable resume mode")
+	}
+	if got := resumed.resumeFiles["A001/clip.mov"].Hash.XXHash64; got != "00000000deadbeef" {
+		t.Errorf("journaled hash not carried over, got %q", got)
+	}
+
+	// Resume trusts a journaled hash only where the copy was verified
+	prev := make(map[string]map[string]hash.HashResult)
+	previousFromJournal(prev, resumed.Config.Destinations, resumed.resumeFiles)
+	if _, ok := prev["/raid"]["A001/clip.mov"]; !ok {
+		t.Error("hash of the copy verified on /raid not trusted")
+	}
+	if _, ok := prev["/shuttle"]["A001/clip.mov"]; ok {
+		t.Error("hash trusted on /shuttle, where verification failed")
+	}
+	for _, dst := range resumed.Config.Destinations {
+		if _, ok := prev[dst]["A001/clip2.mov"]; ok {
+			t.Errorf("hash of a copy never read back trusted on %s", dst)
+		}
+	}
+	if len(q.Failed) != 0 || len(q.Pending) != 1 {
+		t.Errorf("after Resume: %d failed, %d pending; want 0, 1", len(q.Failed), len(q.Pending))
+	}
+}
+
+func TestJournal_RecordsFinishedRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_journal_run_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "card")
+	dst := filepath.Join(dir, "raid")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.mov", "b.mov"} {
+		if err := ioutil.WriteFile(filepath.Join(src, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Source = src
+	cfg.Destinations = []string{dst}
+	cfg.MetadataMode = "off"
+	j := NewJob(cfg)
+
+	journalDir := filepath.Join(dir, "journal")
+	if j.Journal, err = OpenJournal(journalDir, j.ID); err != nil {
+		t.Fatal(err)
+	}
+	updates := make(chan Msg, 1000)
+	j.Run(updates)
+	j.Journal.Close()
+
+	if j.Status != StatusCompleted {
+		t.Fatalf("job status = %s (%v)", j.Status, j.Err)
+	}
+	e, err := ReadJournal(filepath.Join(journalDir, j.ID+journalExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Finished() || e.Status != StatusCompleted {
+		t.Errorf("journal status = %s, want %s", e.Status, StatusCompleted)
+	}
+	if len(e.Files) != 2 || e.Files["a.mov"].Hash == (hash.HashResult{}) {
+		t.Errorf("journal files = %+v, want both files with hashes", e.Files)
+	}
+	if v := e.Files["a.mov"].Verified; len(v) != 1 || v[0] != dst {
+		t.Errorf("a.mov verified on %v, want [%s]", v, dst)
+	}
+}
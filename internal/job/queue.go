@@ -22,6 +22,9 @@ type Queue struct {
 
 	UpdateChan chan QueueState
 
+	// JournalDir enables on-disk journaling of every job run (empty: off)
+	JournalDir string
+
 	mutex sync.Mutex
 	quit  chan struct{}
 }
@@ -78,14 +81,25 @@ func (q *Queue) process(jobUpdates chan Msg) {
 				// Broadcast start (Active changed)
 				q.broadcastState()
 
+				if q.JournalDir != "" {
+					// Best effort: a job still runs if its journal can't be written
+					if jl, err := OpenJournal(q.JournalDir, nextJob.ID); err == nil {
+						nextJob.Journal = jl
+					}
+				}
+
 				// Run the job (blocking)
 				// We pass the updates channel so UI gets messages
 				nextJob.Run(jobUpdates)
 
+				if nextJob.Journal != nil {
+					nextJob.Journal.Close()
+				}
+
 				// Job finished
 				q.mutex.Lock()
 				q.Active = nil
-				if nextJob.Status == StatusFailed {
+				if nextJob.State() == StatusFailed {
 					q.Failed = append(q.Failed, nextJob)
 				} else {
 					q.Completed = append(q.Completed, nextJob)
@@ -124,6 +138,80 @@ func (q *Queue) CancelJob(id string) {
 	}
 }
 
+// PauseJob pauses the active job if it has the given ID.
+// Only a copying or verifying job can be paused.
+func (q *Queue) PauseJob(id string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.Active == nil || q.Active.ID != id || !q.Active.Pause() {
+		return false
+	}
+	q.broadcastState()
+	return true
+}
+
+// UnpauseJob continues the active job if it has the given ID and is paused
+func (q *Queue) UnpauseJob(id string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.Active == nil || q.Active.ID != id || !q.Active.Unpause() {
+		return false
+	}
+	q.broadcastState()
+	return true
+}
+
+// Restore loads the jobs journaled in JournalDir: finished ones as history,
+// interrupted ones into Failed with StatusInterrupted so they can be resumed.
+// It returns the number of interrupted jobs.
+func (q *Queue) Restore() (int, error) {
+	if q.JournalDir == "" {
+		return 0, nil
+	}
+	entries, err := LoadJournals(q.JournalDir)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	interrupted := 0
+	for _, e := range entries {
+		j := RestoreJob(e)
+		switch j.Status {
+		case StatusCompleted:
+			q.Completed = append(q.Completed, j)
+		case StatusInterrupted:
+			interrupted++
+			fallthrough
+		default:
+			q.Failed = append(q.Failed, j)
+		}
+	}
+	return interrupted, nil
+}
+
+// Resume queues a new run of a finished or interrupted job in resume mode.
+// The old entry is dropped from history since the new run shares its ID.
+func (q *Queue) Resume(id string) (*Job, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, list := range []*[]*Job{&q.Failed, &q.Completed} {
+		for i, j := range *list {
+			if j.ID != id {
+				continue
+			}
+			*list = append((*list)[:i], (*list)[i+1:]...)
+			nj := j.Resume()
+			q.Pending = append(q.Pending, nj)
+			q.broadcastState()
+			return nj, true
+		}
+	}
+	return nil, false
+}
+
 // Snapshot returns a safe copy of the current queue state
 func (q *Queue) Snapshot() (active *Job, pending, completed, failed []*Job) {
 	q.mutex.Lock()
@@ -1,8 +1,13 @@
 package job
 
 import (
-	"loot/internal/config"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"loot/internal/config"
 )
 
 func TestQueue_Add(t *testing.T) {
@@ -68,3 +73,105 @@ func TestQueue_Cancel_Pending(t *testing.T) {
 		t.Error("Cancelled job missing from Failed list")
 	}
 }
+
+func TestQueue_PauseActive(t *testing.T) {
+	q := NewQueue()
+	j := NewJob(config.DefaultConfig())
+	other := NewJob(config.DefaultConfig())
+	q.Add(other)
+
+	// Only a copying/verifying job can be paused
+	q.Active = j
+	j.Status = StatusRunning
+	if q.PauseJob(j.ID) {
+		t.Error("a job in preflight should not pause")
+	}
+	j.Status = StatusCopying
+	if q.PauseJob(other.ID) {
+		t.Error("a pending job should not pause")
+	}
+
+	if !q.PauseJob(j.ID) {
+		t.Fatal("PauseJob failed")
+	}
+	if j.Status != StatusPaused || !j.Offloader.Paused() {
+		t.Errorf("status = %s, offloader paused = %v", j.Status, j.Offloader.Paused())
+	}
+	if q.PauseJob(j.ID) {
+		t.Error("pausing twice should fail")
+	}
+
+	// A stage change while paused is applied on continue
+	j.enterStage(StatusVerifying)
+	if j.Status != StatusPaused {
+		t.Errorf("status = %s, want still paused", j.Status)
+	}
+	if !q.UnpauseJob(j.ID) {
+		t.Fatal("UnpauseJob failed")
+	}
+	if j.Status != StatusVerifying || j.Offloader.Paused() {
+		t.Errorf("status = %s, offloader paused = %v after continue", j.Status, j.Offloader.Paused())
+	}
+	if q.UnpauseJob(j.ID) {
+		t.Error("continuing a running job should fail")
+	}
+
+	// A job that ends while paused doesn't stay held
+	if !q.PauseJob(j.ID) {
+		t.Fatal("PauseJob failed")
+	}
+	j.setStatus(StatusCompleted)
+	if j.State() != StatusCompleted || j.Offloader.Paused() {
+		t.Errorf("status = %s, offloader paused = %v after finishing", j.State(), j.Offloader.Paused())
+	}
+}
+
+// Run with go test -race: status changes from Run, Pause and Unpause interleave
+func TestJob_PauseWhileRunning(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_pause_race_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "card")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if err := ioutil.WriteFile(filepath.Join(src, fmt.Sprintf("clip%02d.mov", i)), make([]byte, 64*1024), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := config.DefaultConfig()
+	cfg.Source = src
+	cfg.Destinations = []string{filepath.Join(dir, "raid")}
+	cfg.MetadataMode = "off"
+
+	j := NewJob(cfg)
+	updates := make(chan Msg)
+	done := make(chan struct{})
+	go func() {
+		for range updates {
+		}
+	}()
+	go func() {
+		j.Run(updates)
+		close(done)
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+			if j.Pause() {
+				j.Unpause()
+			}
+			_ = j.State()
+		}
+	}
+	close(updates)
+	if j.State() != StatusCompleted || j.Offloader.Paused() {
+		t.Errorf("status = %s (%v), offloader paused = %v", j.State(), j.Err, j.Offloader.Paused())
+	}
+}
@@ -59,6 +59,17 @@ func IsAvailable() bool {
 	return err == nil
 }
 
+// mediaExts are the camera and video file extensions metadata is read from
+var mediaExts = map[string]bool{
+	".mov": true, ".mp4": true, ".mxf": true, ".mkv": true, ".avi": true,
+	".r3d": true, ".braw": true, ".crm": true, ".ari": true,
+}
+
+// IsMediaExt reports whether ext (any case) is a camera or video file
+func IsMediaExt(ext string) bool {
+	return mediaExts[strings.ToLower(ext)]
+}
+
 // Extract retrieves metadata using specified mode
 // modes: "hybrid" (default), "header", "exiftool", "off"
 func Extract(path string, mode string) (*Metadata, error) {
@@ -67,12 +78,7 @@ func Extract(path string, mode string) (*Metadata, error) {
 	}
 
 	// Only process video/audio extensions to save time/errors
-	ext := strings.ToLower(filepath.Ext(path))
-	knownExts := map[string]bool{
-		".mov": true, ".mp4": true, ".mxf": true, ".mkv": true, ".avi": true,
-		".r3d": true, ".braw": true, ".crm": true, ".ari": true,
-	}
-	if !knownExts[ext] {
+	if !IsMediaExt(filepath.Ext(path)) {
 		return nil, nil // Not a supported media file
 	}
 
@@ -88,7 +94,7 @@ func Extract(path string, mode string) (*Metadata, error) {
 			}
 
 			// Hybrid: If we have enough info, return early (Speed: <5ms)
-			if m.Resolution != "" && m.FrameRate != "" {
+			if mediaMeta.Complete || (m.Resolution != "" && m.FrameRate != "") {
 				return m, nil
 			}
 
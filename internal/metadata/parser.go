@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -32,6 +33,10 @@ type MediaMetadata struct {
 	ColorTemperature int
 	VideoFormat      string
 	Quality          string
+
+	// Complete is set by parsers that read everything the format holds, so
+	// hybrid mode doesn't fall back to ExifTool for the missing fields
+	Complete bool
 }
 
 // ToMetadata converts MediaMetadata to the main Metadata struct
@@ -50,18 +55,65 @@ func (m *MediaMetadata) ToMetadata() *Metadata {
 	}
 }
 
-// Parser extracts metadata from file headers
+// Region is a byte range of a file. A negative Offset counts back from
+// the end of the file.
+type Region struct {
+	Offset int64
+	Length int64
+}
+
+// Parser extracts metadata from media files. It gets random access to the
+// whole file, so metadata written at the end (MP4 moov, MXF footer
+// partition) is in reach.
 type Parser interface {
 	// CanHandle returns true if this parser can handle the file extension
 	CanHandle(ext string) bool
 
+	// Regions lists the parts of a file of the given size that Parse reads
+	// first (header, footer...). ParseHeader reads them ahead in one go;
+	// Parse may still read elsewhere, e.g. at an offset found in a header.
+	Regions(size int64) []Region
+
+	// Parse extracts metadata from r, a file of the given size
+	Parse(r io.ReaderAt, size int64) (*MediaMetadata, error)
+
+	// Name returns parser name for debugging
+	Name() string
+}
+
+// HeaderParser is a parser that only needs the start of a file, read as a
+// stream. FromHeaderParser turns it into a Parser.
+type HeaderParser interface {
+	CanHandle(ext string) bool
+
 	// Parse extracts metadata from reader (limited to maxBytes)
 	Parse(r io.Reader, maxBytes int) (*MediaMetadata, error)
 
-	// Name returns parser name for debugging
 	Name() string
 }
 
+// headerLimit is how much of a file a HeaderParser gets (128KB is quick to
+// read and enough for R3D and ARRIRAW headers)
+const headerLimit = 128 * 1024
+
+// FromHeaderParser adapts a HeaderParser: it is handed the first 128KB
+func FromHeaderParser(p HeaderParser) Parser {
+	return headerAdapter{p}
+}
+
+type headerAdapter struct {
+	HeaderParser
+}
+
+func (a headerAdapter) Regions(size int64) []Region {
+	return []Region{{Offset: 0, Length: min(size, headerLimit)}}
+}
+
+func (a headerAdapter) Parse(r io.ReaderAt, size int64) (*MediaMetadata, error) {
+	n := min(size, headerLimit)
+	return a.HeaderParser.Parse(io.NewSectionReader(r, 0, n), int(n))
+}
+
 // ParserRegistry manages available parsers
 type ParserRegistry struct {
 	parsers []Parser
@@ -103,11 +155,50 @@ func ParseHeader(path string) (*MediaMetadata, error) {
 	}
 	defer f.Close()
 
-	// Read only header (128KB max for speed, sufficient for most headers)
-	// Some formats like R3D have headers at start. MOV/MP4 might have moov atom at end (requires seeking, not just Reader)
-	// For R3D, header is at start.
-	const headerLimit = 128 * 1024
-	lr := io.LimitReader(f, headerLimit)
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	meta, err := parser.Parse(prefetch(f, parser.Regions(size), size), size)
+	if err != nil {
+		return nil, err
+	}
+	fillFromName(meta, path)
+	return meta, nil
+}
+
+// rollName matches the roll naming of cinema cameras: clips A001C002_230101AB
+// (ARRI, Sony, Canon), A001_10211234_C002 (Blackmagic), A001_C002_0412XY
+// (RED) and rolls A001R1AB or A001_0412AB are all camera A, reel A001. The
+// reel must be followed by a letter, "_" or nothing, so C0001.MP4 is not one.
+var rollName = regexp.MustCompile(`^([A-Z])(\d{3})(?:[A-Z_]|$)`)
+
+// RollFromName derives the camera letter and reel from a clip or roll name,
+// e.g. "A001C002_230101AB" -> "A", "A001". Both are empty if it doesn't match.
+func RollFromName(name string) (camera, reel string) {
+	m := rollName.FindStringSubmatch(strings.ToUpper(name))
+	if m == nil {
+		return "", ""
+	}
+	return m[1], m[1] + m[2]
+}
 
-	return parser.Parse(lr, headerLimit)
+// fillFromName completes clip name, camera and reel from the file name
+// when the header doesn't carry them (cameras name files after the clip)
+func fillFromName(meta *MediaMetadata, path string) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	// A frame of a sequence is named after its clip
+	if clip, _, ok := SequenceFrame(path); ok {
+		name = filepath.Base(clip)
+	}
+	if meta.ClipName == "" {
+		meta.ClipName = name
+	}
+	// The clip name from the header first, then the file name
+	for _, n := range []string{meta.ClipName, name} {
+		if meta.CameraID == "" && meta.ReelNumber == "" {
+			meta.CameraID, meta.ReelNumber = RollFromName(n)
+		}
+	}
 }
@@ -0,0 +1,108 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"loot/internal/metadata"
+)
+
+// ARIParser reads the header of an ARRIRAW frame (.ari). Every frame of a
+// clip carries the same 4KB little-endian header ahead of the image data,
+// so one frame describes the whole sequence.
+type ARIParser struct{}
+
+func init() {
+	metadata.RegisterParser(metadata.FromHeaderParser(&ARIParser{}))
+}
+
+func (p *ARIParser) Name() string {
+	return "ARRIRAW"
+}
+
+func (p *ARIParser) CanHandle(ext string) bool {
+	return strings.ToLower(ext) == ".ari"
+}
+
+// ARRIRAW header fields. Geometry and model as read by LibRaw; frame rates
+// are in thousandths of a frame per second, strings are NUL terminated.
+const (
+	ariHeaderSize  = 4096
+	ariWidth       = 0x14
+	ariHeight      = 0x18
+	ariCameraModel = 0x29C // 64 bytes
+	ariSensorFPS   = 0x30C
+	ariProjectFPS  = 0x310
+	ariTimecode    = 0x314 // BCD hh mm ss ff, most significant byte first
+	ariCameraIndex = 0x318 // Camera letter, 1 byte
+	ariReelName    = 0x320 // 16 bytes
+	ariClipName    = 0x330 // 48 bytes
+)
+
+func (p *ARIParser) Parse(r io.Reader, maxBytes int) (*metadata.MediaMetadata, error) {
+	header := make([]byte, ariHeaderSize)
+	n, err := io.ReadFull(r, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("failed to read ARRIRAW header: %w", err)
+	}
+	if n < ariClipName+48 {
+		return nil, fmt.Errorf("ARRIRAW header too small: %d bytes", n)
+	}
+	if string(header[0:4]) != "ARRI" {
+		return nil, fmt.Errorf("invalid ARRIRAW magic: %q", header[0:4])
+	}
+
+	meta := &metadata.MediaMetadata{Source: "ari_header", Format: "ARRIRAW", Codec: "ARRIRAW"}
+	meta.Width = int(binary.LittleEndian.Uint32(header[ariWidth:]))
+	meta.Height = int(binary.LittleEndian.Uint32(header[ariHeight:]))
+	if meta.Width > 0 && meta.Height > 0 {
+		meta.Resolution = fmt.Sprintf("%dx%d", meta.Width, meta.Height)
+	}
+	meta.CameraModel = cString(header[ariCameraModel : ariCameraModel+64])
+	meta.ReelNumber = cString(header[ariReelName : ariReelName+16])
+	meta.ClipName = cString(header[ariClipName : ariClipName+48])
+	if c := header[ariCameraIndex]; c >= 'A' && c <= 'Z' {
+		meta.CameraID = string(c)
+	}
+
+	// Playback (project) rate; the sensor rate differs for off-speed shots
+	fps := binary.LittleEndian.Uint32(header[ariProjectFPS:])
+	if fps == 0 {
+		fps = binary.LittleEndian.Uint32(header[ariSensorFPS:])
+	}
+	if fps > 0 {
+		meta.FPS = fmt.Sprintf("%.3f", float64(fps)/1000)
+	}
+	meta.Timecode = bcdTimecode(binary.BigEndian.Uint32(header[ariTimecode:]))
+
+	meta.Complete = hasEssentials(meta)
+	return meta, nil
+}
+
+// cString returns the text of a NUL terminated, fixed size field
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// bcdTimecode formats a BCD hh:mm:ss:ff timecode, or "" if it is unset
+// (all zero) or not valid BCD
+func bcdTimecode(v uint32) string {
+	var f [4]int
+	for i := range f {
+		b := byte(v >> (24 - 8*i))
+		if b>>4 > 9 || b&0x0F > 9 {
+			return ""
+		}
+		f[i] = int(b>>4)*10 + int(b&0x0F)
+	}
+	if v == 0 || f[0] > 23 || f[1] > 59 || f[2] > 59 {
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", f[0], f[1], f[2], f[3])
+}
@@ -0,0 +1,120 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"loot/internal/metadata"
+)
+
+// ariClip holds the clip fields of an ARRIRAW header
+type ariClip struct {
+	sensorFPS, projectFPS uint32 // Thousandths of a frame per second
+	timecode              uint32 // BCD hhmmssff
+	camera                byte
+	reel, name            string
+}
+
+// testARI builds an ARRIRAW frame: the 4KB header and a little image data
+func testARI(width, height uint32, model string, clip ariClip) []byte {
+	b := make([]byte, ariHeaderSize+1024)
+	copy(b, "ARRI")
+	binary.LittleEndian.PutUint32(b[4:], 0x12345678)
+	binary.LittleEndian.PutUint32(b[8:], ariHeaderSize)
+	binary.LittleEndian.PutUint32(b[ariWidth:], width)
+	binary.LittleEndian.PutUint32(b[ariHeight:], height)
+	copy(b[ariCameraModel:], model)
+	binary.LittleEndian.PutUint32(b[ariSensorFPS:], clip.sensorFPS)
+	binary.LittleEndian.PutUint32(b[ariProjectFPS:], clip.projectFPS)
+	binary.BigEndian.PutUint32(b[ariTimecode:], clip.timecode)
+	b[ariCameraIndex] = clip.camera
+	copy(b[ariReelName:], clip.reel)
+	copy(b[ariClipName:], clip.name)
+	return b
+}
+
+func TestARIParser(t *testing.T) {
+	p := &ARIParser{}
+	// Shot at 48 fps for 25 fps playback
+	clip := ariClip{sensorFPS: 48000, projectFPS: 25000, timecode: 0x14000012, camera: 'B', reel: "B002R1AB", name: "B002C003_230101_R1AB"}
+	meta, err := p.Parse(bytes.NewReader(testARI(4448, 3096, "ALEXA Mini LF", clip)), 128*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := metadata.MediaMetadata{
+		Source: "ari_header", Format: "ARRIRAW", Codec: "ARRIRAW",
+		Resolution: "4448x3096", Width: 4448, Height: 3096, FPS: "25.000", Timecode: "14:00:00:12",
+		CameraModel: "ALEXA Mini LF", CameraID: "B", ReelNumber: "B002R1AB", ClipName: "B002C003_230101_R1AB",
+		Complete: true,
+	}
+	if *meta != want {
+		t.Errorf("got %+v\nwant %+v", *meta, want)
+	}
+
+	// Without a project rate the sensor rate is used
+	meta, err = p.Parse(bytes.NewReader(testARI(3424, 2202, "ALEXA SXT", ariClip{sensorFPS: 23976})), 128*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.FPS != "23.976" || meta.Timecode != "" || !meta.Complete {
+		t.Errorf("sensor rate only: got %+v", *meta)
+	}
+
+	// No frame rate at all: incomplete, so hybrid mode still asks ExifTool
+	meta, err = p.Parse(bytes.NewReader(testARI(3424, 2202, "ALEXA SXT", ariClip{})), 128*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.FPS != "" || meta.Complete {
+		t.Errorf("no frame rate: got %+v, want incomplete", *meta)
+	}
+
+	if _, err := p.Parse(bytes.NewReader(testMOV(true, 8192)), 128*1024); err == nil {
+		t.Error("non-ARRIRAW data should be an error")
+	}
+}
+
+func TestParseHeaderNamesFrameAfterClip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_ari_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "B003C012_230101_R2CD.0000042.ari")
+	if err := ioutil.WriteFile(path, testARI(3424, 2202, "ALEXA SXT", ariClip{projectFPS: 24000}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := metadata.ParseHeader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.ClipName != "B003C012_230101_R2CD" || meta.CameraID != "B" || meta.ReelNumber != "B003" {
+		t.Errorf("ParseHeader = %+v", meta)
+	}
+}
+
+func TestSequenceFrame(t *testing.T) {
+	tests := []struct {
+		path  string
+		clip  string
+		frame int
+		ok    bool
+	}{
+		{"A001C001_230101_R1AB.0000123.ari", "A001C001_230101_R1AB", 123, true},
+		{filepath.Join("A001", "A001C001_230101_R1AB_0086400.ARI"), filepath.Join("A001", "A001C001_230101_R1AB"), 86400, true},
+		{filepath.Join("A001C002", "0000007.ari"), "A001C002", 7, true},
+		{"A001C001_230101_R1AB.ari", "", 0, false},
+		{"A001C001.0000001.mov", "", 0, false},
+	}
+	for _, tt := range tests {
+		clip, frame, ok := metadata.SequenceFrame(tt.path)
+		if clip != tt.clip || frame != tt.frame || ok != tt.ok {
+			t.Errorf("SequenceFrame(%q) = %q, %d, %v", tt.path, clip, frame, ok)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package parsers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"loot/internal/metadata"
+)
+
+// BRAWParser reads Blackmagic RAW clips (.braw). They are QuickTime files:
+// picture geometry, frame rate and timecode come from the tracks, camera
+// and slate information from the "mdta" metadata in moov.
+type BRAWParser struct{}
+
+func init() {
+	metadata.RegisterParser(&BRAWParser{})
+}
+
+func (p *BRAWParser) Name() string {
+	return "BRAW"
+}
+
+func (p *BRAWParser) CanHandle(ext string) bool {
+	return strings.ToLower(ext) == ".braw"
+}
+
+// Regions are those of QuickTime: moov may be at either end
+func (p *BRAWParser) Regions(size int64) []metadata.Region {
+	return movRegions
+}
+
+// brawKeys maps Blackmagic RAW clip metadata keys to MediaMetadata fields
+var brawKeys = map[string]func(m *metadata.MediaMetadata, v string){
+	"camera_type":   func(m *metadata.MediaMetadata, v string) { m.CameraModel = v },
+	"camera_id":     func(m *metadata.MediaMetadata, v string) { m.SerialNumber = v },
+	"camera_number": func(m *metadata.MediaMetadata, v string) { m.CameraID = v },
+	"reel_name":     func(m *metadata.MediaMetadata, v string) { m.ReelNumber = v },
+	"clip_name":     func(m *metadata.MediaMetadata, v string) { m.ClipName = v },
+	"take":          func(m *metadata.MediaMetadata, v string) { m.TakeNumber = v },
+}
+
+func (p *BRAWParser) Parse(r io.ReaderAt, size int64) (*metadata.MediaMetadata, error) {
+	_, moov, err := readMoov(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("BRAW: %w", err)
+	}
+
+	meta := &metadata.MediaMetadata{Source: "braw_header", Format: "BRAW"}
+	parseMoov(r, moov, meta)
+	// Sample descriptions are brxq, brst, brvn, brs2... per quality setting
+	if !strings.HasPrefix(meta.VideoFormat, "br") {
+		return nil, fmt.Errorf("not a Blackmagic RAW file (video format %q)", meta.VideoFormat)
+	}
+	meta.Codec = "Blackmagic RAW"
+
+	for key, value := range metadataItems(moov) {
+		if set, ok := brawKeys[key]; ok && value != "" {
+			set(meta, value)
+		}
+	}
+	meta.Complete = hasEssentials(meta)
+	return meta, nil
+}
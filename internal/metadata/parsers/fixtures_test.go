@@ -0,0 +1,185 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"loot/internal/metadata"
+)
+
+// loadDump decodes a `hexdump -C` style dump like header.dump: an optional
+// "Read N bytes" line, offset + hex + ASCII rows, "*" for repeated rows and
+// a final offset line giving the length.
+func loadDump(t *testing.T, path string) []byte {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var data, last []byte
+	repeat := false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "*" {
+			repeat = true
+			continue
+		}
+		off, err := strconv.ParseUint(fields[0], 16, 64)
+		if err != nil || len(fields[0]) != 8 {
+			continue // "Read N bytes" and other notes
+		}
+		if repeat {
+			for uint64(len(data)) < off {
+				data = append(data, last...)
+			}
+			data = data[:off]
+			repeat = false
+		}
+		if uint64(len(data)) != off {
+			t.Fatalf("%s: offset %08x follows %d bytes", path, off, len(data))
+		}
+
+		var row []byte
+		for _, h := range fields[1:] {
+			if strings.HasPrefix(h, "|") {
+				break
+			}
+			b, err := strconv.ParseUint(h, 16, 8)
+			if err != nil || len(h) != 2 {
+				t.Fatalf("%s: bad byte %q at %08x", path, h, off)
+			}
+			row = append(row, byte(b))
+		}
+		if len(row) > 0 {
+			data, last = append(data, row...), row
+		}
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// The dumps in testdata are synthetic clips laid out like camera originals
+// (ftyp, mdat, then moov; an ARRIRAW frame's 4KB header), trimmed to a few
+// KB of media data. A dump of a
+// real clip can be dropped next to them with its expected fields added here.
+func TestHeaderFixtures(t *testing.T) {
+	want := map[string]metadata.MediaMetadata{
+		"A001_10211234_C001.braw": {
+			Source: "braw_header", Format: "BRAW", Codec: "Blackmagic RAW", VideoFormat: "brxq",
+			Resolution: "6144x3456", Width: 6144, Height: 3456, FPS: "25.000",
+			Duration: "2s", DurationFrames: 50, Timecode: "10:00:00:00",
+			CameraModel: "Blackmagic Pocket Cinema Camera 6K G2", SerialNumber: "7E2C41A9",
+			CameraID: "A", ReelNumber: "1", ClipName: "A001_10211234_C001", TakeNumber: "3",
+			Complete: true,
+		},
+		"A002C003_230101AB.crm": {
+			Source: "crm_header", Format: "CRM", Codec: "Cinema RAW Light", VideoFormat: "CRAW",
+			Resolution: "4096x2160", Width: 4096, Height: 2160, FPS: "23.976",
+			Duration: "2.002s", DurationFrames: 48, Timecode: "01:00:00:00",
+			CameraModel: "Canon EOS C70", SerialNumber: "073021000123",
+			CameraID: "A", ReelNumber: "A002", ClipName: "A002C003_230101AB",
+			Complete: true,
+		},
+		"A001C003_230101_R1AB.0000001.ari": {
+			Source: "ari_header", Format: "ARRIRAW", Codec: "ARRIRAW",
+			Resolution: "4448x3096", Width: 4448, Height: 3096, FPS: "23.976", Timecode: "10:21:34:05",
+			CameraModel: "ALEXA Mini LF", CameraID: "A", ReelNumber: "A001R1AB", ClipName: "A001C003_230101_R1AB",
+			Complete: true,
+		},
+	}
+
+	dumps, err := filepath.Glob(filepath.Join("testdata", "*.dump"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "loot_fixtures_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	seen := 0
+	for _, dump := range dumps {
+		name := strings.TrimSuffix(filepath.Base(dump), ".dump")
+		w, ok := want[name]
+		if !ok {
+			t.Errorf("%s: no expected metadata", dump)
+			continue
+		}
+		seen++
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, loadDump(t, dump), 0644); err != nil {
+			t.Fatal(err)
+		}
+		meta, err := metadata.ParseHeader(path)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if *meta != w {
+			t.Errorf("%s:\n got %+v\nwant %+v", name, *meta, w)
+		}
+	}
+	if seen != len(want) {
+		t.Errorf("found %d of %d fixtures", seen, len(want))
+	}
+}
+
+func TestRAWParsersRejectOtherFiles(t *testing.T) {
+	mov := testMOV(true, 16)
+	if _, err := parseBytes(&BRAWParser{}, mov); err == nil {
+		t.Error("BRAW parser accepted a ProRes file")
+	}
+	if _, err := parseBytes(&CRMParser{}, mov); err == nil {
+		t.Error("CRM parser accepted a QuickTime file")
+	}
+}
+
+func TestBRAWCompleteNeedsEssentials(t *testing.T) {
+	braw := bytes.Replace(testMOV(true, 16), []byte("apch"), []byte("brxq"), 1)
+	meta, err := parseBytes(&BRAWParser{}, braw)
+	if err != nil || !meta.Complete {
+		t.Fatalf("full BRAW = %+v, %v; want complete", meta, err)
+	}
+
+	// Without its stts atom the clip has no frame rate
+	noRate := bytes.Replace(braw, []byte("stts"), []byte("free"), 1)
+	meta, err = parseBytes(&BRAWParser{}, noRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.FPS != "" || meta.Complete {
+		t.Errorf("BRAW without frame rate = %+v, want incomplete", meta)
+	}
+}
+
+func TestRollFromName(t *testing.T) {
+	tests := []struct{ name, camera, reel string }{
+		{"A001C002_230101AB", "A", "A001"},
+		{"b012c001_2301016x", "B", "B012"},
+		{"A001_10211234_C001", "A", "A001"},
+		{"A002_C001_0412XY", "A", "A002"},
+		{"A010R1AB", "A", "A010"},
+		{"C0001", "", ""},
+		{"Interview", "", ""},
+	}
+	for _, tt := range tests {
+		if camera, reel := metadata.RollFromName(tt.name); camera != tt.camera || reel != tt.reel {
+			t.Errorf("RollFromName(%q) = %q, %q", tt.name, camera, reel)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package parsers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"loot/internal/metadata"
+)
+
+// MOVParser reads QuickTime / MP4 files (.mov, .mp4) by walking their atoms.
+// The moov atom may sit after the media data (cameras write it last), so the
+// parser seeks to it rather than relying on the first bytes of the file.
+type MOVParser struct{}
+
+func init() {
+	metadata.RegisterParser(&MOVParser{})
+}
+
+func (p *MOVParser) Name() string {
+	return "QuickTime"
+}
+
+func (p *MOVParser) CanHandle(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".mov", ".mp4", ".m4v":
+		return true
+	}
+	return false
+}
+
+// Regions covers ftyp and a "fast start" moov at the head of the file, and
+// the moov that cameras write after the media data
+func (p *MOVParser) Regions(size int64) []metadata.Region {
+	return movRegions
+}
+
+var movRegions = []metadata.Region{{Offset: 0, Length: 64 * 1024}, {Offset: -1024 * 1024, Length: 1024 * 1024}}
+
+// maxMoovSize bounds the moov atom read into memory. Real files stay far
+// below this, even for long takes.
+const maxMoovSize = 64 * 1024 * 1024
+
+// atom is a QuickTime atom (an MP4 "box"): its type and payload
+type atom struct {
+	typ  string
+	data []byte
+}
+
+// Parse finds the ftyp and moov atoms anywhere in the file
+func (p *MOVParser) Parse(r io.ReaderAt, size int64) (*metadata.MediaMetadata, error) {
+	brand, moov, err := readMoov(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &metadata.MediaMetadata{Source: "mov_header", Format: "QuickTime"}
+	if brand != "" && brand != "qt  " {
+		meta.Format = "MP4"
+	}
+	parseMoov(r, moov, meta)
+	meta.Complete = hasEssentials(meta)
+	return meta, nil
+}
+
+// hasEssentials reports whether codec, resolution and frame rate were all
+// found. Otherwise the metadata isn't Complete and hybrid mode still asks
+// ExifTool.
+func hasEssentials(meta *metadata.MediaMetadata) bool {
+	return meta.Codec != "" && meta.Resolution != "" && meta.FPS != ""
+}
+
+// readMoov walks the top-level atoms of a QuickTime / ISO media file and
+// returns its major brand (empty without ftyp) and the moov payload
+func readMoov(r io.ReaderAt, size int64) (brand string, moov []byte, err error) {
+	hdr := make([]byte, 16)
+	for off := int64(0); off+8 <= size; {
+		if err := readAt(r, hdr[:8], off); err != nil {
+			return "", nil, fmt.Errorf("failed to read atom at %d: %w", off, err)
+		}
+		atomSize, typ, hdrLen := int64(binary.BigEndian.Uint32(hdr[0:4])), string(hdr[4:8]), int64(8)
+		switch atomSize {
+		case 0: // Runs to the end of the file
+			atomSize = size - off
+		case 1: // 64-bit size follows the type
+			if err := readAt(r, hdr[8:16], off+8); err != nil {
+				return "", nil, fmt.Errorf("failed to read atom at %d: %w", off, err)
+			}
+			atomSize, hdrLen = int64(binary.BigEndian.Uint64(hdr[8:16])), 16
+		}
+		if atomSize < hdrLen || off+atomSize > size {
+			if off == 0 {
+				return "", nil, fmt.Errorf("not a QuickTime file")
+			}
+			break // Truncated file: keep what we found
+		}
+		if off == 0 && !topLevelAtoms[typ] {
+			return "", nil, fmt.Errorf("not a QuickTime file (first atom %q)", typ)
+		}
+
+		switch typ {
+		case "ftyp":
+			if atomSize >= hdrLen+4 {
+				b := make([]byte, 4)
+				if err := readAt(r, b, off+hdrLen); err == nil {
+					brand = string(b)
+				}
+			}
+		case "moov":
+			if atomSize-hdrLen > maxMoovSize {
+				return "", nil, fmt.Errorf("moov atom too large: %d bytes", atomSize)
+			}
+			moov = make([]byte, atomSize-hdrLen)
+			if err := readAt(r, moov, off+hdrLen); err != nil {
+				return "", nil, fmt.Errorf("failed to read moov atom: %w", err)
+			}
+		}
+		if moov != nil {
+			break
+		}
+		off += atomSize
+	}
+	if moov == nil {
+		return "", nil, fmt.Errorf("no moov atom found")
+	}
+	return brand, moov, nil
+}
+
+// topLevelAtoms are the atoms a QuickTime or MP4 file can start with
+var topLevelAtoms = map[string]bool{
+	"ftyp": true, "moov": true, "mdat": true, "wide": true, "free": true,
+	"skip": true, "pnot": true, "uuid": true,
+}
+
+// track is what the parser keeps of a trak atom
+type track struct {
+	handler       string // "vide", "soun", "tmcd"...
+	format        string // Sample description FourCC
+	width, height int
+	timescale     uint32
+	duration      uint64
+	samples       uint64 // Sample count from stts
+	sampleTime    uint64 // Sum of sample durations from stts
+	chunkOffset   int64  // First chunk, -1 if unknown
+	tcFlags       uint32 // tmcd only
+	tcFrames      int    // tmcd only: frames per second (nominal)
+}
+
+func parseMoov(r io.ReaderAt, moov []byte, meta *metadata.MediaMetadata) {
+	var tracks []*track
+	for _, a := range children(moov) {
+		switch a.typ {
+		case "mvhd":
+			if timescale, duration, ok := timeFields(a.data); ok && timescale > 0 {
+				meta.Duration = formatSeconds(float64(duration) / float64(timescale))
+			}
+		case "trak":
+			tracks = append(tracks, parseTrak(a.data))
+		}
+	}
+
+	var video, tc *track
+	for _, t := range tracks {
+		switch {
+		case t.handler == "vide" && video == nil:
+			video = t
+		case (t.handler == "tmcd" || t.format == "tmcd") && tc == nil:
+			tc = t
+		}
+	}
+
+	if video != nil {
+		meta.VideoFormat = video.format
+		meta.Codec = codecName(video.format)
+		if video.width > 0 && video.height > 0 {
+			meta.Width, meta.Height = video.width, video.height
+			meta.Resolution = fmt.Sprintf("%dx%d", video.width, video.height)
+		}
+		if video.sampleTime > 0 && video.timescale > 0 {
+			fps := float64(video.samples) * float64(video.timescale) / float64(video.sampleTime)
+			meta.FPS = fmt.Sprintf("%.3f", fps)
+		}
+		meta.DurationFrames = int(video.samples)
+	}
+
+	if tc != nil && tc.tcFrames > 0 && tc.chunkOffset >= 0 {
+		b := make([]byte, 4)
+		if err := readAt(r, b, tc.chunkOffset); err == nil {
+			meta.Timecode = formatTimecode(binary.BigEndian.Uint32(b), tc.tcFrames, tc.tcFlags&tcDropFrame != 0)
+		}
+	}
+}
+
+func parseTrak(data []byte) *track {
+	t := &track{chunkOffset: -1}
+	var walk func(b []byte)
+	walk = func(b []byte) {
+		for _, a := range children(b) {
+			switch a.typ {
+			case "mdia", "minf", "stbl":
+				walk(a.data)
+			case "tkhd":
+				// Width and height are the last two 16.16 fixed-point fields
+				if n := len(a.data); n >= 84 && t.width == 0 {
+					t.width = int(binary.BigEndian.Uint32(a.data[n-8:]) >> 16)
+					t.height = int(binary.BigEndian.Uint32(a.data[n-4:]) >> 16)
+				}
+			case "mdhd":
+				t.timescale, t.duration, _ = timeFields(a.data)
+			case "hdlr":
+				// version/flags, component type, then the handler subtype.
+				// minf has its own data handler ("dhlr", subtype "url "/"alis")
+				// after the media handler: only the latter names the track type.
+				if len(a.data) >= 12 && t.handler == "" && string(a.data[4:8]) != "dhlr" {
+					t.handler = string(a.data[8:12])
+				}
+			case "stsd":
+				parseStsd(a.data, t)
+			case "stts":
+				if len(a.data) < 8 {
+					continue
+				}
+				n := int(binary.BigEndian.Uint32(a.data[4:8]))
+				for i := 0; i < n && 8+i*8+8 <= len(a.data); i++ {
+					e := a.data[8+i*8:]
+					count, delta := uint64(binary.BigEndian.Uint32(e[0:4])), uint64(binary.BigEndian.Uint32(e[4:8]))
+					t.samples += count
+					t.sampleTime += count * delta
+				}
+			case "stco":
+				if len(a.data) >= 12 && binary.BigEndian.Uint32(a.data[4:8]) > 0 {
+					t.chunkOffset = int64(binary.BigEndian.Uint32(a.data[8:12]))
+				}
+			case "co64":
+				if len(a.data) >= 16 && binary.BigEndian.Uint32(a.data[4:8]) > 0 {
+					t.chunkOffset = int64(binary.BigEndian.Uint64(a.data[8:16]))
+				}
+			}
+		}
+	}
+	walk(data)
+	return t
+}
+
+// tmcd flags
+const tcDropFrame = 0x0001
+
+// parseStsd reads the first sample description: its FourCC, the coded size
+// of video entries and the timecode format of tmcd entries
+func parseStsd(data []byte, t *track) {
+	// version/flags, entry count, then entries starting with size and format
+	if len(data) < 16 || binary.BigEndian.Uint32(data[4:8]) == 0 {
+		return
+	}
+	e := data[8:]
+	if size := int(binary.BigEndian.Uint32(e[0:4])); size >= 16 && size <= len(e) {
+		e = e[:size]
+	}
+	t.format = string(e[4:8])
+
+	// Sample entries start with 6 reserved bytes and a data reference index
+	switch {
+	case t.format == "tmcd" && len(e) >= 33:
+		t.tcFlags = binary.BigEndian.Uint32(e[20:24])
+		t.tcFrames = int(e[32])
+	case t.handler == "vide" && len(e) >= 36:
+		// version, revision, vendor, temporal and spatial quality, then size
+		t.width = int(binary.BigEndian.Uint16(e[32:34]))
+		t.height = int(binary.BigEndian.Uint16(e[34:36]))
+	}
+}
+
+// timeFields reads timescale and duration from an mvhd or mdhd payload
+func timeFields(data []byte) (timescale uint32, duration uint64, ok bool) {
+	if len(data) < 4 {
+		return 0, 0, false
+	}
+	if data[0] == 1 { // 64-bit creation/modification times and duration
+		if len(data) < 32 {
+			return 0, 0, false
+		}
+		return binary.BigEndian.Uint32(data[20:24]), binary.BigEndian.Uint64(data[24:32]), true
+	}
+	if len(data) < 20 {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint32(data[12:16]), uint64(binary.BigEndian.Uint32(data[16:20])), true
+}
+
+// children splits a container payload into its atoms
+func children(b []byte) []atom {
+	var atoms []atom
+	for len(b) >= 8 {
+		size, hdrLen := uint64(binary.BigEndian.Uint32(b[0:4])), uint64(8)
+		typ := string(b[4:8])
+		switch size {
+		case 0:
+			size = uint64(len(b))
+		case 1:
+			if len(b) < 16 {
+				return atoms
+			}
+			size, hdrLen = binary.BigEndian.Uint64(b[8:16]), 16
+		}
+		if size < hdrLen || size > uint64(len(b)) {
+			return atoms
+		}
+		atoms = append(atoms, atom{typ: typ, data: b[hdrLen:size]})
+		b = b[size:]
+	}
+	return atoms
+}
+
+// metadataItems reads the QuickTime "mdta" metadata (meta atom with keys
+// and ilst) found directly in moov, in moov/udta or in a track. Values are
+// rendered as strings; the first occurrence of a key wins.
+func metadataItems(moov []byte) map[string]string {
+	items := map[string]string{}
+	var walk func(b []byte)
+	walk = func(b []byte) {
+		for _, a := range children(b) {
+			switch a.typ {
+			case "udta", "trak":
+				walk(a.data)
+			case "meta":
+				parseMeta(a.data, items)
+			}
+		}
+	}
+	walk(moov)
+	return items
+}
+
+func parseMeta(data []byte, items map[string]string) {
+	// ISO meta is a full box, QuickTime's isn't: skip version/flags if present
+	if len(data) >= 4 && binary.BigEndian.Uint32(data[0:4]) == 0 {
+		data = data[4:]
+	}
+	var keys []string
+	var ilst []byte
+	for _, a := range children(data) {
+		switch a.typ {
+		case "keys":
+			// version/flags, entry count, then size, namespace and name per key
+			if len(a.data) < 8 {
+				continue
+			}
+			b := a.data[8:]
+			for n := binary.BigEndian.Uint32(a.data[4:8]); n > 0 && len(b) >= 8; n-- {
+				size := int(binary.BigEndian.Uint32(b[0:4]))
+				if size < 8 || size > len(b) {
+					break
+				}
+				keys = append(keys, string(b[8:size]))
+				b = b[size:]
+			}
+		case "ilst":
+			ilst = a.data
+		}
+	}
+	// ilst items are typed by the 1-based index of their key
+	for _, item := range children(ilst) {
+		i := int(binary.BigEndian.Uint32([]byte(item.typ))) - 1
+		if i < 0 || i >= len(keys) {
+			continue
+		}
+		if _, ok := items[keys[i]]; ok {
+			continue
+		}
+		for _, d := range children(item.data) {
+			if v, ok := dataValue(d); ok {
+				items[keys[i]] = v
+				break
+			}
+		}
+	}
+}
+
+// dataValue renders a "data" atom: type, locale, then the value
+func dataValue(a atom) (string, bool) {
+	if a.typ != "data" || len(a.data) < 8 {
+		return "", false
+	}
+	typ, v := binary.BigEndian.Uint32(a.data[0:4])&0xFFFFFF, a.data[8:]
+	switch typ {
+	case 1: // UTF-8
+		return strings.TrimRight(string(v), "\x00"), true
+	case 21, 22: // Big-endian signed / unsigned integer
+		if len(v) == 0 || len(v) > 8 {
+			return "", false
+		}
+		var n uint64
+		for _, c := range v {
+			n = n<<8 | uint64(c)
+		}
+		if typ == 21 { // Sign-extend
+			shift := 64 - 8*len(v)
+			return fmt.Sprint(int64(n<<shift) >> shift), true
+		}
+		return fmt.Sprint(n), true
+	}
+	return "", false
+}
+
+// readAt fills buf from offset off
+func readAt(r io.ReaderAt, buf []byte, off int64) error {
+	n, err := r.ReadAt(buf, off)
+	if n == len(buf) {
+		return nil // A read ending at EOF may report it
+	}
+	if err == nil {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// codecNames maps sample description FourCCs to readable codec names
+var codecNames = map[string]string{
+	"apco": "Apple ProRes 422 Proxy",
+	"apcs": "Apple ProRes 422 LT",
+	"apcn": "Apple ProRes 422",
+	"apch": "Apple ProRes 422 HQ",
+	"ap4h": "Apple ProRes 4444",
+	"ap4x": "Apple ProRes 4444 XQ",
+	"aprn": "Apple ProRes RAW",
+	"aprh": "Apple ProRes RAW HQ",
+	"avc1": "H.264",
+	"avc3": "H.264",
+	"hvc1": "HEVC",
+	"hev1": "HEVC",
+	"mp4v": "MPEG-4 Visual",
+	"jpeg": "Motion JPEG",
+	"mjpa": "Motion JPEG",
+	"AVdn": "Avid DNxHD",
+	"AVdh": "Avid DNxHR",
+	"xd5c": "XDCAM HD422",
+}
+
+func codecName(fourCC string) string {
+	if name, ok := codecNames[fourCC]; ok {
+		return name
+	}
+	return strings.TrimSpace(fourCC)
+}
+
+// formatSeconds renders a duration the way the ffprobe path does
+func formatSeconds(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond).String()
+}
+
+// formatTimecode turns a frame count into HH:MM:SS:FF (HH:MM:SS;FF for
+// drop-frame, which skips frame numbers 0 and 1 of every minute not
+// divisible by ten at 30 fps, and 0-3 at 60 fps)
+func formatTimecode(frame uint32, fps int, dropFrame bool) string {
+	f := int(frame)
+	sep := ":"
+	if dropFrame && fps%30 == 0 {
+		sep = ";"
+		drop := fps / 15
+		perMin := fps*60 - drop
+		per10Min := perMin*10 + drop
+		tens, rest := f/per10Min, f%per10Min
+		f += 9 * drop * tens
+		if rest > drop {
+			f += drop * ((rest - drop) / perMin)
+		}
+	}
+	ff := f % fps
+	s := f / fps
+	return fmt.Sprintf("%02d:%02d:%02d%s%02d", (s/3600)%24, (s/60)%60, s%60, sep, ff)
+}
@@ -0,0 +1,162 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"loot/internal/metadata"
+)
+
+// box builds an atom from its type and payload parts
+func box(typ string, parts ...[]byte) []byte {
+	payload := bytes.Join(parts, nil)
+	b := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(b, uint32(8+len(payload)))
+	copy(b[4:], typ)
+	return append(b, payload...)
+}
+
+func u32(vs ...uint32) []byte {
+	b := make([]byte, 4*len(vs))
+	for i, v := range vs {
+		binary.BigEndian.PutUint32(b[i*4:], v)
+	}
+	return b
+}
+
+func zeros(n int) []byte { return make([]byte, n) }
+
+// parseBytes runs a parser on an in-memory file
+func parseBytes(p metadata.Parser, b []byte) (*metadata.MediaMetadata, error) {
+	return p.Parse(bytes.NewReader(b), int64(len(b)))
+}
+
+// testMOV builds a ProRes 422 HQ 1920x1080 clip at 23.976 fps (240 frames)
+// with a timecode track starting at 01:00:00:00. With moovFirst the file is
+// "fast start", otherwise moov follows pad bytes of media data like camera
+// originals.
+func testMOV(moovFirst bool, pad int) []byte {
+	ftyp := box("ftyp", []byte("qt  "), u32(0x200), []byte("qt  "))
+	tcSample := u32(24 * 3600) // Frame number of 01:00:00:00 at 24 fps
+	mdatPayload := append(tcSample, zeros(pad)...)
+	mdat := box("mdat", mdatPayload)
+
+	// QuickTime and ffmpeg write a data handler in minf, after the media handler
+	dataHandler := box("hdlr", u32(0), []byte("dhlrurl "), zeros(12))
+	moov := func(tcOffset uint32) []byte {
+		mvhd := box("mvhd", u32(0, 0, 0, 24000, 240240), zeros(80))
+		tkhd := box("tkhd", zeros(76), u32(1920<<16, 1080<<16))
+		videoEntry := append(append(u32(86), []byte("apch")...), zeros(8)...)
+		videoEntry = append(videoEntry, zeros(16)...)
+		videoEntry = append(videoEntry, 0x07, 0x80, 0x04, 0x38) // 1920x1080
+		videoEntry = append(videoEntry, zeros(86-len(videoEntry))...)
+		video := box("trak", tkhd, box("mdia",
+			box("mdhd", u32(0, 0, 0, 24000, 240240, 0)),
+			box("hdlr", u32(0), []byte("mhlrvide"), zeros(12)),
+			box("minf", dataHandler, box("stbl",
+				box("stsd", u32(0, 1), videoEntry),
+				box("stts", u32(0, 1, 240, 1001)),
+			)),
+		))
+
+		tcEntry := append(append(u32(34), []byte("tmcd")...), zeros(8)...)
+		tcEntry = append(tcEntry, u32(0, 0, 24000, 1001)...)
+		tcEntry = append(tcEntry, 24, 0)
+		tc := box("trak", box("tkhd", zeros(84)), box("mdia",
+			box("mdhd", u32(0, 0, 0, 24000, 240240, 0)),
+			box("hdlr", u32(0), []byte("mhlrtmcd"), zeros(12)),
+			box("minf", dataHandler, box("stbl",
+				box("stsd", u32(0, 1), tcEntry),
+				box("stts", u32(0, 1, 1, 240240)),
+				box("stco", u32(0, 1, tcOffset)),
+			)),
+		))
+		return box("moov", mvhd, video, tc)
+	}
+
+	if moovFirst {
+		size := uint32(len(moov(0)))
+		return bytes.Join([][]byte{ftyp, moov(uint32(len(ftyp)) + size + 8), mdat}, nil)
+	}
+	return bytes.Join([][]byte{ftyp, mdat, moov(uint32(len(ftyp)) + 8)}, nil)
+}
+
+func TestMOVParser(t *testing.T) {
+	p := &MOVParser{}
+	for _, moovFirst := range []bool{true, false} {
+		meta, err := parseBytes(p, testMOV(moovFirst, 1024))
+		if err != nil {
+			t.Fatalf("moovFirst=%v: %v", moovFirst, err)
+		}
+		want := metadata.MediaMetadata{
+			Source: "mov_header", Format: "QuickTime", Codec: "Apple ProRes 422 HQ",
+			Resolution: "1920x1080", Width: 1920, Height: 1080, FPS: "23.976",
+			Duration: "10.01s", DurationFrames: 240, Timecode: "01:00:00:00",
+			VideoFormat: "apch", Complete: true,
+		}
+		if *meta != want {
+			t.Errorf("moovFirst=%v:\n got %+v\nwant %+v", moovFirst, *meta, want)
+		}
+	}
+
+	// A file cut before its trailing moov has no metadata
+	if _, err := parseBytes(p, testMOV(false, 1024)[:64]); err == nil {
+		t.Error("Parse should fail without the moov atom")
+	}
+
+	if _, err := parseBytes(p, []byte("RIFF\x00\x00\x00\x10WAVEfmt ")); err == nil {
+		t.Error("non-QuickTime data should be an error")
+	}
+}
+
+func TestParseHeaderSeeksToMoov(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_mov_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// moov after 512KB of media data, well past the start of the file
+	data := testMOV(false, 512*1024)
+	path := filepath.Join(dir, "A001C001.mov")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := metadata.ParseHeader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Timecode != "01:00:00:00" || meta.FPS != "23.976" {
+		t.Errorf("ParseHeader = %+v", meta)
+	}
+
+	m, err := metadata.Extract(path, "hybrid")
+	if err != nil || m == nil || m.Codec != "Apple ProRes 422 HQ" {
+		t.Errorf("Extract(hybrid) = %+v, %v", m, err)
+	}
+}
+
+func TestFormatTimecode(t *testing.T) {
+	tests := []struct {
+		frame uint32
+		fps   int
+		drop  bool
+		want  string
+	}{
+		{24 * 3600, 24, false, "01:00:00:00"},
+		{25*61 + 3, 25, false, "00:01:01:03"},
+		{1800, 30, true, "00:01:00;02"},
+		{17982, 30, true, "00:10:00;00"},
+		{107892, 30, true, "01:00:00;00"},
+		{3600, 60, true, "00:01:00;04"},
+	}
+	for _, tt := range tests {
+		if got := formatTimecode(tt.frame, tt.fps, tt.drop); got != tt.want {
+			t.Errorf("formatTimecode(%d, %d, %v) = %s, want %s", tt.frame, tt.fps, tt.drop, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,507 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+
+	"loot/internal/metadata"
+)
+
+// MXFParser reads MXF (SMPTE 377) clips: the header partition pack and the
+// header metadata sets that follow it. When the header partition is
+// incomplete (the camera wrote the final metadata at close time), the
+// footer partition is read instead.
+type MXFParser struct{}
+
+func init() {
+	metadata.RegisterParser(&MXFParser{})
+}
+
+func (p *MXFParser) Name() string {
+	return "MXF"
+}
+
+func (p *MXFParser) CanHandle(ext string) bool {
+	return strings.ToLower(ext) == ".mxf"
+}
+
+// Regions covers the run-in and header partition, and the footer partition
+// written when the camera closes the clip
+func (p *MXFParser) Regions(size int64) []metadata.Region {
+	return []metadata.Region{{Offset: 0, Length: 1024 * 1024}, {Offset: -1024 * 1024, Length: 1024 * 1024}}
+}
+
+const (
+	// maxRunIn is the largest run-in allowed before the header partition
+	maxRunIn = 64 * 1024
+	// maxHeaderMetadata bounds the header metadata read into memory
+	maxHeaderMetadata = 16 * 1024 * 1024
+)
+
+// Universal label prefixes. Byte 7 is a registry version and is ignored
+// when comparing labels.
+var (
+	ulPrefix      = []byte{0x06, 0x0E, 0x2B, 0x34}
+	partitionPack = []byte{0x06, 0x0E, 0x2B, 0x34, 0x02, 0x05, 0x01, 0x01, 0x0D, 0x01, 0x02, 0x01, 0x01}
+	metadataSet   = []byte{0x06, 0x0E, 0x2B, 0x34, 0x02, 0x53, 0x01, 0x01, 0x0D, 0x01, 0x01, 0x01, 0x01}
+)
+
+// Header metadata set types (byte 14 of the set key)
+const (
+	setTimecode        = 0x14
+	setRGBADescriptor  = 0x29
+	setIdentification  = 0x30
+	setMaterialPackage = 0x36
+	setTaggedValue     = 0x3F
+)
+
+// Static local tags of SMPTE 377
+const (
+	tagInstanceUID       = 0x3C0A
+	tagCompanyName       = 0x3C01
+	tagProductName       = 0x3C02
+	tagPackageName       = 0x4402
+	tagPackageTracks     = 0x4403
+	tagPackageComments   = 0x4406
+	tagTrackSequence     = 0x4803
+	tagEditRate          = 0x4B01
+	tagDataDefinition    = 0x0201
+	tagDuration          = 0x0202
+	tagComponents        = 0x1001
+	tagStartTimecode     = 0x1501
+	tagTimecodeBase      = 0x1502
+	tagDropFrame         = 0x1503
+	tagSampleRate        = 0x3001
+	tagContainerDuration = 0x3002
+	tagPictureCoding     = 0x3201
+	tagStoredHeight      = 0x3202
+	tagStoredWidth       = 0x3203
+	tagFrameLayout       = 0x320C
+	tagTaggedName        = 0x5001
+	tagTaggedValue       = 0x5003
+)
+
+// Partition status (byte 14 of the partition pack key)
+const (
+	partitionOpenIncomplete   = 0x01
+	partitionClosedIncomplete = 0x02
+)
+
+// mxfSet is a header metadata local set
+type mxfSet struct {
+	typ   byte
+	props map[uint16][]byte
+}
+
+// mxfHeader is the header metadata of one partition, sets by instance UID
+type mxfHeader struct {
+	sets  []*mxfSet
+	byUID map[string]*mxfSet
+}
+
+// Parse finds the header partition (after an optional run-in) and decodes
+// its metadata
+func (p *MXFParser) Parse(r io.ReaderAt, size int64) (*metadata.MediaMetadata, error) {
+	head := make([]byte, min(size, maxRunIn+16))
+	if err := readAt(r, head, 0); err != nil {
+		return nil, fmt.Errorf("failed to read MXF header: %w", err)
+	}
+	start := bytes.Index(head, partitionPack)
+	if start < 0 {
+		return nil, fmt.Errorf("not an MXF file (no header partition)")
+	}
+
+	status, footer, h, err := readPartition(r, int64(start), size)
+	if err != nil {
+		return nil, err
+	}
+	// Cameras often leave the header open and write the final metadata in the footer
+	if (status == partitionOpenIncomplete || status == partitionClosedIncomplete || h.first(setMaterialPackage) == nil) && footer > 0 {
+		if _, _, fh, err := readPartition(r, int64(start)+footer, size); err == nil && fh.first(setMaterialPackage) != nil {
+			h = fh
+		}
+	}
+	if h.first(setMaterialPackage) == nil {
+		return nil, fmt.Errorf("no material package in MXF header metadata")
+	}
+
+	meta := &metadata.MediaMetadata{Source: "mxf_header", Format: "MXF"}
+	h.fill(meta)
+	meta.Complete = hasEssentials(meta)
+	return meta, nil
+}
+
+// readPartition reads the partition pack at off and the header metadata
+// following it. It returns the partition status and the footer offset.
+func readPartition(r io.ReaderAt, off, size int64) (byte, int64, *mxfHeader, error) {
+	key, value, next, err := readKLV(r, off, size, 1024)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to read MXF partition pack: %w", err)
+	}
+	if !ulEqual(key[:len(partitionPack)], partitionPack) || len(value) < 48 {
+		return 0, 0, nil, fmt.Errorf("invalid MXF partition pack at %d", off)
+	}
+	status := key[14]
+	footer := int64(binary.BigEndian.Uint64(value[24:32]))
+	headerBytes := int64(binary.BigEndian.Uint64(value[32:40]))
+
+	// Without a byte count, read a bounded window and stop at the first
+	// KLV that isn't header metadata
+	n := headerBytes
+	if n <= 0 || n > maxHeaderMetadata {
+		n = maxHeaderMetadata
+	}
+	n = min(n, size-next)
+	buf := make([]byte, n)
+	if err := readAt(r, buf, next); err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to read MXF header metadata: %w", err)
+	}
+	return status, footer, parseHeaderMetadata(buf), nil
+}
+
+// readKLV reads the key-length-value triplet at off, refusing values over max
+func readKLV(r io.ReaderAt, off, size int64, max int) (key, value []byte, next int64, err error) {
+	hdr := make([]byte, min(25, size-off))
+	if len(hdr) < 17 {
+		return nil, nil, 0, io.ErrUnexpectedEOF
+	}
+	if err := readAt(r, hdr, off); err != nil {
+		return nil, nil, 0, err
+	}
+	length, n, ok := berLength(hdr[16:])
+	if !ok || length > uint64(max) {
+		return nil, nil, 0, fmt.Errorf("bad KLV length at %d", off)
+	}
+	value = make([]byte, length)
+	if err := readAt(r, value, off+16+int64(n)); err != nil {
+		return nil, nil, 0, err
+	}
+	return hdr[:16], value, off + 16 + int64(n) + int64(length), nil
+}
+
+// berLength decodes a BER length, returning it and the bytes it took
+func berLength(b []byte) (uint64, int, bool) {
+	if len(b) == 0 {
+		return 0, 0, false
+	}
+	if b[0] < 0x80 {
+		return uint64(b[0]), 1, true
+	}
+	n := int(b[0] & 0x7F)
+	if n == 0 || n > 8 || len(b) < 1+n {
+		return 0, 0, false
+	}
+	var l uint64
+	for _, c := range b[1 : 1+n] {
+		l = l<<8 | uint64(c)
+	}
+	return l, 1 + n, true
+}
+
+// parseHeaderMetadata decodes the local sets in b (primer and fill are skipped)
+func parseHeaderMetadata(b []byte) *mxfHeader {
+	h := &mxfHeader{byUID: make(map[string]*mxfSet)}
+	for len(b) >= 17 {
+		key := b[:16]
+		if !bytes.Equal(key[:4], ulPrefix) {
+			break
+		}
+		length, n, ok := berLength(b[16:])
+		if !ok || uint64(len(b)-16-n) < length {
+			break
+		}
+		value := b[16+n : 16+n+int(length)]
+		b = b[16+n+int(length):]
+
+		switch {
+		case ulEqual(key[:len(metadataSet)], metadataSet):
+			s := &mxfSet{typ: key[14], props: localProps(value)}
+			h.sets = append(h.sets, s)
+			if uid, ok := s.props[tagInstanceUID]; ok {
+				h.byUID[string(uid)] = s
+			}
+		case ulEqual(key[:len(partitionPack)], partitionPack), key[4] == 0x01 && key[5] == 0x02:
+			return h // Next partition or essence: end of the header metadata
+		}
+	}
+	return h
+}
+
+// localProps splits a local set into its tag -> value properties
+func localProps(b []byte) map[uint16][]byte {
+	props := make(map[uint16][]byte)
+	for len(b) >= 4 {
+		tag, l := binary.BigEndian.Uint16(b[0:2]), int(binary.BigEndian.Uint16(b[2:4]))
+		if len(b) < 4+l {
+			break
+		}
+		props[tag] = b[4 : 4+l]
+		b = b[4+l:]
+	}
+	return props
+}
+
+// ulEqual compares two labels, ignoring the version byte
+func ulEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if i != 7 && a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *mxfHeader) first(typ byte) *mxfSet {
+	for _, s := range h.sets {
+		if s.typ == typ {
+			return s
+		}
+	}
+	return nil
+}
+
+// refs resolves a batch of strong references
+func (h *mxfHeader) refs(batch []byte) []*mxfSet {
+	if len(batch) < 8 {
+		return nil
+	}
+	count, size := int(binary.BigEndian.Uint32(batch[0:4])), int(binary.BigEndian.Uint32(batch[4:8]))
+	var sets []*mxfSet
+	for i := 0; i < count && size > 0 && 8+(i+1)*size <= len(batch); i++ {
+		if s, ok := h.byUID[string(batch[8+i*size:8+(i+1)*size])]; ok {
+			sets = append(sets, s)
+		}
+	}
+	return sets
+}
+
+// Data definitions of tracks (byte 11 and 12 of the label)
+const (
+	dataTimecode = 0x01
+	dataEssence  = 0x02
+	dataPicture  = 0x01
+)
+
+// fill copies what the header metadata holds into meta
+func (h *mxfHeader) fill(meta *metadata.MediaMetadata) {
+	pkg := h.first(setMaterialPackage)
+	meta.ClipName = utf16String(pkg.props[tagPackageName])
+
+	var company string
+	if id := h.first(setIdentification); id != nil {
+		company = utf16String(id.props[tagCompanyName])
+		meta.CameraModel = utf16String(id.props[tagProductName])
+	}
+
+	// Material package tracks: picture edit rate and duration, start timecode
+	var editNum, editDen uint32
+	for _, track := range h.refs(pkg.props[tagPackageTracks]) {
+		seq, ok := h.byUID[string(track.props[tagTrackSequence])]
+		if !ok {
+			continue
+		}
+		def := seq.props[tagDataDefinition]
+		switch {
+		case len(def) == 16 && def[11] == dataEssence && def[12] == dataPicture && editNum == 0:
+			editNum, editDen = rational(track.props[tagEditRate])
+			meta.DurationFrames = int(int64Prop(seq.props[tagDuration]))
+		case len(def) == 16 && def[11] == dataTimecode && meta.Timecode == "":
+			for _, c := range h.refs(seq.props[tagComponents]) {
+				if c.typ != setTimecode {
+					continue
+				}
+				base := int(uint16Prop(c.props[tagTimecodeBase]))
+				drop := len(c.props[tagDropFrame]) > 0 && c.props[tagDropFrame][0] != 0
+				if base > 0 {
+					meta.Timecode = formatTimecode(uint32(int64Prop(c.props[tagStartTimecode])), base, drop)
+				}
+				break
+			}
+		}
+	}
+
+	// Picture descriptor: essence coding and stored size
+	var desc *mxfSet
+	for _, s := range h.sets {
+		if _, ok := s.props[tagStoredWidth]; ok {
+			desc = s
+			break
+		}
+	}
+	if desc != nil {
+		meta.Width = int(uint32Prop(desc.props[tagStoredWidth]))
+		meta.Height = int(uint32Prop(desc.props[tagStoredHeight]))
+		// Separate fields: the stored height is per field
+		if layout := desc.props[tagFrameLayout]; len(layout) > 0 && layout[0] == 1 {
+			meta.Height *= 2
+		}
+		if meta.Width > 0 && meta.Height > 0 {
+			meta.Resolution = fmt.Sprintf("%dx%d", meta.Width, meta.Height)
+		}
+		meta.Codec = mxfCodec(desc.props[tagPictureCoding], desc.typ == setRGBADescriptor, company, meta.CameraModel)
+		if editNum == 0 {
+			editNum, editDen = rational(desc.props[tagSampleRate])
+		}
+		if meta.DurationFrames == 0 {
+			meta.DurationFrames = int(int64Prop(desc.props[tagContainerDuration]))
+		}
+	}
+
+	if editNum > 0 && editDen > 0 {
+		meta.FPS = fmt.Sprintf("%.3f", float64(editNum)/float64(editDen))
+		if meta.DurationFrames > 0 {
+			meta.Duration = formatSeconds(float64(meta.DurationFrames) * float64(editDen) / float64(editNum))
+		}
+	}
+
+	// Camera and reel from the descriptive metadata, else from a package
+	// name like A001C002_230101AB (ParseHeader then tries the file name)
+	h.fillRoll(meta, pkg)
+	if meta.CameraID == "" && meta.ReelNumber == "" {
+		meta.CameraID, meta.ReelNumber = metadata.RollFromName(meta.ClipName)
+	}
+}
+
+// rollComments maps user comment names (lower case, without spaces and
+// underscores) to the field they hold
+var rollComments = map[string]func(m *metadata.MediaMetadata, v string){
+	"reel":         func(m *metadata.MediaMetadata, v string) { m.ReelNumber = v },
+	"reelname":     func(m *metadata.MediaMetadata, v string) { m.ReelNumber = v },
+	"reelnumber":   func(m *metadata.MediaMetadata, v string) { m.ReelNumber = v },
+	"tapename":     func(m *metadata.MediaMetadata, v string) { m.ReelNumber = v },
+	"camera":       func(m *metadata.MediaMetadata, v string) { m.CameraID = v },
+	"cameraid":     func(m *metadata.MediaMetadata, v string) { m.CameraID = v },
+	"cameraindex":  func(m *metadata.MediaMetadata, v string) { m.CameraID = v },
+	"cameraletter": func(m *metadata.MediaMetadata, v string) { m.CameraID = v },
+}
+
+// fillRoll reads camera and reel from the user comments of the material
+// package: tagged values (name, indirect value) written with the clip
+func (h *mxfHeader) fillRoll(meta *metadata.MediaMetadata, pkg *mxfSet) {
+	for _, c := range h.refs(pkg.props[tagPackageComments]) {
+		if c.typ != setTaggedValue {
+			continue
+		}
+		name := strings.NewReplacer(" ", "", "_", "").Replace(strings.ToLower(utf16String(c.props[tagTaggedName])))
+		if set, ok := rollComments[name]; ok {
+			if v := indirectString(c.props[tagTaggedValue]); v != "" {
+				set(meta, v)
+			}
+		}
+	}
+}
+
+// proResNames are the SMPTE RDD 44 ProRes labels by their last byte
+var proResNames = map[byte]string{
+	0x01: "Apple ProRes 422 Proxy",
+	0x02: "Apple ProRes 422 LT",
+	0x03: "Apple ProRes 422",
+	0x04: "Apple ProRes 422 HQ",
+	0x05: "Apple ProRes 4444",
+	0x06: "Apple ProRes 4444 XQ",
+}
+
+// mxfCodec names the picture essence coding label. ARRIRAW has no public
+// coding label: it's recognised as RGBA essence written by an ARRI camera.
+func mxfCodec(ul []byte, rgba bool, company, product string) string {
+	vendor := strings.ToUpper(company + " " + product)
+	if rgba && (strings.Contains(vendor, "ARRI") || strings.Contains(vendor, "ALEXA")) {
+		return "ARRIRAW"
+	}
+	if len(ul) != 16 || !bytes.Equal(ul[:4], ulPrefix) {
+		return ""
+	}
+	c := ul[8:]
+	switch {
+	case c[0] == 0x04 && c[1] == 0x01 && c[2] == 0x02 && c[3] == 0x01:
+		return "Uncompressed"
+	case c[0] != 0x04 || c[1] != 0x01 || c[2] != 0x02 || c[3] != 0x02:
+		return ""
+	case c[4] == 0x01 && (c[5] == 0x31 || c[5] == 0x32): // H.264 / AVC-Intra
+		switch {
+		case strings.Contains(vendor, "SONY"):
+			return "XAVC"
+		case strings.Contains(vendor, "CANON"):
+			return "XF-AVC"
+		}
+		return "H.264"
+	case c[4] == 0x01 && c[5] == 0x20:
+		return "MPEG-4 Visual"
+	case c[4] == 0x01 && c[5] < 0x20:
+		return "MPEG-2"
+	case c[4] == 0x03 && c[5] == 0x01:
+		return "JPEG 2000"
+	case c[4] == 0x03 && c[5] == 0x06:
+		if name, ok := proResNames[c[6]]; ok {
+			return name
+		}
+		return "Apple ProRes"
+	case c[4] == 0x71:
+		return "DNxHD"
+	}
+	return ""
+}
+
+func utf16String(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		c := binary.BigEndian.Uint16(b[i:])
+		if c == 0 {
+			break
+		}
+		u = append(u, c)
+	}
+	return strings.TrimSpace(string(utf16.Decode(u)))
+}
+
+// indirectString decodes an indirect string property: a byte order flag
+// ('L' little, 'B' big endian), the 16 byte type label, then UTF-16 text
+func indirectString(b []byte) string {
+	if len(b) < 17 {
+		return ""
+	}
+	order, text := b[0], b[17:]
+	if order != 'L' {
+		return utf16String(text)
+	}
+	swapped := make([]byte, len(text)&^1)
+	for i := 0; i+1 < len(text); i += 2 {
+		swapped[i], swapped[i+1] = text[i+1], text[i]
+	}
+	return utf16String(swapped)
+}
+
+func rational(b []byte) (num, den uint32) {
+	if len(b) < 8 {
+		return 0, 0
+	}
+	return binary.BigEndian.Uint32(b[0:4]), binary.BigEndian.Uint32(b[4:8])
+}
+
+func uint16Prop(b []byte) uint16 {
+	if len(b) < 2 {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func uint32Prop(b []byte) uint32 {
+	if len(b) < 4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func int64Prop(b []byte) int64 {
+	if len(b) < 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
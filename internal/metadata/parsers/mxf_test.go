@@ -0,0 +1,269 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"loot/internal/metadata"
+)
+
+// klv builds a KLV triplet with a 4-byte BER length, as MXF writers do
+func klv(key, value []byte) []byte {
+	b := append([]byte{}, key...)
+	b = append(b, 0x83, byte(len(value)>>16), byte(len(value)>>8), byte(len(value)))
+	return append(b, value...)
+}
+
+// tag is one property of a local set
+func tag(t uint16, v []byte) []byte {
+	b := make([]byte, 4, 4+len(v))
+	binary.BigEndian.PutUint16(b, t)
+	binary.BigEndian.PutUint16(b[2:], uint16(len(v)))
+	return append(b, v...)
+}
+
+func localSet(typ byte, props ...[]byte) []byte {
+	key := append(append([]byte{}, metadataSet...), 0x01, typ, 0x00)
+	return klv(key, bytes.Join(props, nil))
+}
+
+func uid(n byte) []byte { return bytes.Repeat([]byte{n}, 16) }
+
+func batch(refs ...[]byte) []byte {
+	return append(u32(uint32(len(refs)), 16), bytes.Join(refs, nil)...)
+}
+
+func utf16be(s string) []byte {
+	var b []byte
+	for _, c := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(c>>8), byte(c))
+	}
+	return b
+}
+
+func u16(v uint16) []byte { return []byte{byte(v >> 8), byte(v)} }
+
+func u64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+// partition builds a partition pack (kind 0x02 header, 0x04 footer)
+func partition(kind, status byte, this, footer, headerBytes uint64) []byte {
+	key := append(append([]byte{}, partitionPack...), kind, status, 0x00)
+	value := bytes.Join([][]byte{
+		u16(1), u16(3), u32(1), u64(this), u64(0), u64(footer), u64(headerBytes),
+		u64(0), u32(0), u64(0), u32(1), zeros(16), u32(0, 16),
+	}, nil)
+	return klv(key, value)
+}
+
+var (
+	ulPicture  = []byte{0x06, 0x0E, 0x2B, 0x34, 0x04, 0x01, 0x01, 0x01, 0x01, 0x03, 0x02, 0x02, 0x01, 0x00, 0x00, 0x00}
+	ulTimecode = []byte{0x06, 0x0E, 0x2B, 0x34, 0x04, 0x01, 0x01, 0x01, 0x01, 0x03, 0x02, 0x01, 0x01, 0x00, 0x00, 0x00}
+	ulAVCIntra = []byte{0x06, 0x0E, 0x2B, 0x34, 0x04, 0x01, 0x01, 0x0A, 0x04, 0x01, 0x02, 0x02, 0x01, 0x32, 0x21, 0x01}
+	ulDNxHD    = []byte{0x06, 0x0E, 0x2B, 0x34, 0x04, 0x01, 0x01, 0x0A, 0x04, 0x01, 0x02, 0x02, 0x71, 0x01, 0x00, 0x00}
+	ulEssence  = []byte{0x06, 0x0E, 0x2B, 0x34, 0x01, 0x02, 0x01, 0x01, 0x0D, 0x01, 0x03, 0x01, 0x15, 0x01, 0x05, 0x00}
+)
+
+type mxfClip struct {
+	company, product, name string
+	descriptor             byte // 0x28 CDCI, 0x29 RGBA
+	coding                 []byte
+	width, height          uint32
+	layout                 byte
+	editNum, editDen       uint32
+	tcBase                 uint16
+	tcStart                uint64
+	drop                   bool
+	frames                 uint64
+	inFooter               bool        // Header left open, metadata in the footer
+	comments               [][2]string // Package user comments (name, value)
+}
+
+// headerMetadata builds the sets of a clip: identification, a material
+// package with a picture and a timecode track, and a picture descriptor
+func (c mxfClip) headerMetadata() []byte {
+	drop := []byte{0}
+	if c.drop {
+		drop = []byte{1}
+	}
+	// Tagged values hold an indirect string: byte order, type label, UTF-16
+	var commentRefs, comments [][]byte
+	for i, kv := range c.comments {
+		ref := uid(byte(0x20 + i))
+		value := append(append([]byte{'B'}, zeros(16)...), utf16be(kv[1])...)
+		commentRefs = append(commentRefs, ref)
+		comments = append(comments, localSet(0x3F, tag(tagInstanceUID, ref), tag(tagTaggedName, utf16be(kv[0])), tag(tagTaggedValue, value)))
+	}
+	descriptor := []byte(nil)
+	if c.descriptor != 0 {
+		descriptor = localSet(c.descriptor, tag(tagInstanceUID, uid(8)), tag(tagSampleRate, u32(c.editNum, c.editDen)),
+			tag(tagPictureCoding, c.coding), tag(tagStoredWidth, u32(c.width)), tag(tagStoredHeight, u32(c.height)),
+			tag(tagFrameLayout, []byte{c.layout}))
+	}
+	return bytes.Join([][]byte{
+		localSet(0x30, tag(tagInstanceUID, uid(1)), tag(tagCompanyName, utf16be(c.company)), tag(tagProductName, utf16be(c.product))),
+		localSet(0x36, tag(tagInstanceUID, uid(2)), tag(tagPackageName, utf16be(c.name)), tag(tagPackageTracks, batch(uid(3), uid(4))),
+			tag(tagPackageComments, batch(commentRefs...))),
+		bytes.Join(comments, nil),
+		localSet(0x3B, tag(tagInstanceUID, uid(3)), tag(tagEditRate, u32(uint32(c.tcBase), 1)), tag(tagTrackSequence, uid(5))),
+		localSet(0x0F, tag(tagInstanceUID, uid(5)), tag(tagDataDefinition, ulTimecode), tag(tagDuration, u64(c.frames)), tag(tagComponents, batch(uid(6)))),
+		localSet(0x14, tag(tagInstanceUID, uid(6)), tag(tagTimecodeBase, u16(c.tcBase)), tag(tagStartTimecode, u64(c.tcStart)), tag(tagDropFrame, drop)),
+		localSet(0x3B, tag(tagInstanceUID, uid(4)), tag(tagEditRate, u32(c.editNum, c.editDen)), tag(tagTrackSequence, uid(7))),
+		localSet(0x0F, tag(tagInstanceUID, uid(7)), tag(tagDataDefinition, ulPicture), tag(tagDuration, u64(c.frames))),
+		descriptor,
+	}, nil)
+}
+
+// bytes lays out a run-in, the header partition, one essence element and
+// the footer partition
+func (c mxfClip) bytes() []byte {
+	runIn := []byte("RUN-IN")
+	md := c.headerMetadata()
+	essence := klv(ulEssence, zeros(4096))
+
+	var header []byte
+	if c.inFooter {
+		header = partition(0x02, 0x01, 0, 0, 0)
+	} else {
+		header = partition(0x02, 0x04, 0, 0, uint64(len(md)))
+		header = append(header, md...)
+	}
+	footerAt := uint64(len(header) + len(essence))
+	// Patch the footer offset into the header partition pack (after key, BER length and 24 bytes)
+	binary.BigEndian.PutUint64(header[16+4+24:], footerAt)
+
+	footer := partition(0x04, 0x04, footerAt, footerAt, 0)
+	if c.inFooter {
+		footer = partition(0x04, 0x04, footerAt, footerAt, uint64(len(md)))
+		footer = append(footer, md...)
+	}
+	return bytes.Join([][]byte{runIn, header, essence, footer}, nil)
+}
+
+func TestMXFParser(t *testing.T) {
+	sony := mxfClip{
+		company: "Sony", product: "ILME-FX6", name: "A001C002_230101AB",
+		descriptor: 0x28, coding: ulAVCIntra, width: 3840, height: 2160,
+		editNum: 24000, editDen: 1001, tcBase: 24, tcStart: 24 * 3600, frames: 240,
+	}
+	arri := mxfClip{
+		company: "ARRI", product: "ALEXA Mini LF", name: "B002C003_230101_R1AB",
+		descriptor: 0x29, coding: zeros(16), width: 4448, height: 3096,
+		editNum: 25, editDen: 1, tcBase: 25, tcStart: 25*3600*14 + 12, frames: 50,
+	}
+	dnx := mxfClip{
+		company: "Avid Technology", product: "Media Composer", name: "Interview",
+		descriptor: 0x28, coding: ulDNxHD, width: 1920, height: 540, layout: 1,
+		editNum: 30000, editDen: 1001, tcBase: 30, tcStart: 1800, drop: true, frames: 300,
+	}
+	footer := sony
+	footer.inFooter = true
+	// Camera and reel from the descriptive metadata win over the package name
+	tagged := sony
+	tagged.name = "Interview_01"
+	tagged.comments = [][2]string{{"Reel Name", "C042"}, {"Camera_ID", "C"}, {"Scene", "12"}}
+	// No picture descriptor: codec and resolution are left to ExifTool
+	bare := sony
+	bare.descriptor = 0
+
+	tests := []struct {
+		name    string
+		clip    mxfClip
+		want    metadata.MediaMetadata
+		partial bool
+	}{
+		{"Sony XAVC", sony, metadata.MediaMetadata{
+			Codec: "XAVC", Resolution: "3840x2160", Width: 3840, Height: 2160, FPS: "23.976",
+			Duration: "10.01s", DurationFrames: 240, Timecode: "01:00:00:00",
+			CameraModel: "ILME-FX6", CameraID: "A", ReelNumber: "A001", ClipName: "A001C002_230101AB",
+		}, false},
+		{"ARRIRAW", arri, metadata.MediaMetadata{
+			Codec: "ARRIRAW", Resolution: "4448x3096", Width: 4448, Height: 3096, FPS: "25.000",
+			Duration: "2s", DurationFrames: 50, Timecode: "14:00:00:12",
+			CameraModel: "ALEXA Mini LF", CameraID: "B", ReelNumber: "B002", ClipName: "B002C003_230101_R1AB",
+		}, false},
+		{"DNxHD interlaced", dnx, metadata.MediaMetadata{
+			Codec: "DNxHD", Resolution: "1920x1080", Width: 1920, Height: 1080, FPS: "29.970",
+			Duration: "10.01s", DurationFrames: 300, Timecode: "00:01:00;02",
+			CameraModel: "Media Composer", ClipName: "Interview",
+		}, false},
+		{"metadata in footer", footer, metadata.MediaMetadata{
+			Codec: "XAVC", Resolution: "3840x2160", Width: 3840, Height: 2160, FPS: "23.976",
+			Duration: "10.01s", DurationFrames: 240, Timecode: "01:00:00:00",
+			CameraModel: "ILME-FX6", CameraID: "A", ReelNumber: "A001", ClipName: "A001C002_230101AB",
+		}, false},
+		{"descriptive metadata", tagged, metadata.MediaMetadata{
+			Codec: "XAVC", Resolution: "3840x2160", Width: 3840, Height: 2160, FPS: "23.976",
+			Duration: "10.01s", DurationFrames: 240, Timecode: "01:00:00:00",
+			CameraModel: "ILME-FX6", CameraID: "C", ReelNumber: "C042", ClipName: "Interview_01",
+		}, false},
+		{"no descriptor", bare, metadata.MediaMetadata{
+			FPS: "23.976", Duration: "10.01s", DurationFrames: 240, Timecode: "01:00:00:00",
+			CameraModel: "ILME-FX6", CameraID: "A", ReelNumber: "A001", ClipName: "A001C002_230101AB",
+		}, true},
+	}
+
+	p := &MXFParser{}
+	for _, tt := range tests {
+		meta, err := parseBytes(p, tt.clip.bytes())
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		tt.want.Source, tt.want.Format, tt.want.Complete = "mxf_header", "MXF", !tt.partial
+		if *meta != tt.want {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.name, *meta, tt.want)
+		}
+	}
+
+	if _, err := parseBytes(p, testMOV(true, 16)); err == nil {
+		t.Error("non-MXF data should be an error")
+	}
+}
+
+func TestExtractMXFWithoutExifTool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_mxf_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	clip := mxfClip{
+		company: "Canon", product: "EOS C70", name: "A003C001_230101XY",
+		descriptor: 0x28, coding: ulAVCIntra, width: 4096, height: 2160,
+		editNum: 25, editDen: 1, tcBase: 25, frames: 25, inFooter: true,
+	}
+	path := filepath.Join(dir, "A003C001.MXF")
+	if err := ioutil.WriteFile(path, clip.bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := metadata.Extract(path, "header")
+	if err != nil || m == nil {
+		t.Fatalf("Extract(header) = %v, %v", m, err)
+	}
+	if m.Codec != "XF-AVC" || m.Resolution != "4096x2160" || m.Timecode != "00:00:00:00" || m.ReelNumber != "A003" {
+		t.Errorf("Extract(header) = %+v", m)
+	}
+
+	// Without a roll in the header, the file name is the fallback
+	clip.name = "Interview"
+	path = filepath.Join(dir, "A004C001_230101XY.MXF")
+	if err := ioutil.WriteFile(path, clip.bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := metadata.ParseHeader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.ClipName != "Interview" || meta.CameraID != "A" || meta.ReelNumber != "A004" {
+		t.Errorf("ParseHeader = %+v, want clip Interview on camera A, reel A004", meta)
+	}
+}
@@ -12,7 +12,7 @@ import (
 type R3DParser struct{}
 
 func init() {
-	metadata.RegisterParser(&R3DParser{})
+	metadata.RegisterParser(metadata.FromHeaderParser(&R3DParser{}))
 }
 
 func (p *R3DParser) Name() string {
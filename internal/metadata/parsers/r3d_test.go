@@ -0,0 +1,38 @@
+package parsers
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"loot/internal/metadata"
+)
+
+func TestR3DThroughHeaderAdapter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_r3d_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Header fields at the offsets R3DParser reads, then 1MB of frame data
+	data := make([]byte, 1024*1024)
+	copy(data[4:], "RED2")
+	binary.BigEndian.PutUint32(data[0x4C:], 8192)
+	binary.BigEndian.PutUint32(data[0x50:], 4320)
+	binary.BigEndian.PutUint32(data[0x58:], 23976)
+	path := filepath.Join(dir, "A001_C002_0101AB_001.R3D")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := metadata.ParseHeader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Source != "r3d_header" || meta.Resolution != "8192x4320" || meta.FPS != "23.976" {
+		t.Errorf("ParseHeader = %+v", meta)
+	}
+}
@@ -0,0 +1,52 @@
+package metadata
+
+import (
+	"io"
+)
+
+// maxPrefetch bounds the bytes read ahead for a single region
+const maxPrefetch = 4 * 1024 * 1024
+
+// regionReader serves reads within the prefetched regions from memory and
+// everything else from the file
+type regionReader struct {
+	r       io.ReaderAt
+	regions []cachedRegion
+}
+
+type cachedRegion struct {
+	off  int64
+	data []byte
+}
+
+// prefetch reads the regions of r (a file of the given size) ahead. Regions
+// are clamped to the file; one that can't be read is left to the file.
+func prefetch(r io.ReaderAt, regions []Region, size int64) io.ReaderAt {
+	rr := &regionReader{r: r}
+	for _, reg := range regions {
+		off := reg.Offset
+		if off < 0 {
+			off += size
+		}
+		off = max(off, 0)
+		n := min(reg.Length, size-off, maxPrefetch)
+		if n <= 0 {
+			continue
+		}
+		data := make([]byte, n)
+		if m, _ := r.ReadAt(data, off); m < len(data) {
+			continue
+		}
+		rr.regions = append(rr.regions, cachedRegion{off: off, data: data})
+	}
+	return rr
+}
+
+func (rr *regionReader) ReadAt(p []byte, off int64) (int, error) {
+	for _, c := range rr.regions {
+		if off >= c.off && off+int64(len(p)) <= c.off+int64(len(c.data)) {
+			return copy(p, c.data[off-c.off:]), nil
+		}
+	}
+	return rr.r.ReadAt(p, off)
+}
@@ -0,0 +1,84 @@
+package metadata
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// countingReader records the reads that reach the underlying file
+type countingReader struct {
+	r     io.ReaderAt
+	reads int
+}
+
+func (c *countingReader) ReadAt(p []byte, off int64) (int, error) {
+	c.reads++
+	return c.r.ReadAt(p, off)
+}
+
+func TestPrefetchServesRegions(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	file := &countingReader{r: bytes.NewReader(data)}
+	r := prefetch(file, []Region{{Offset: 0, Length: 100}, {Offset: -50, Length: 500}}, int64(len(data)))
+	file.reads = 0
+
+	buf := make([]byte, 10)
+	for _, off := range []int64{0, 90, 950, 990} {
+		if n, err := r.ReadAt(buf, off); n != 10 || err != nil || buf[0] != byte(off) {
+			t.Errorf("ReadAt(%d) = %d, %v, %v", off, n, err, buf)
+		}
+	}
+	if file.reads != 0 {
+		t.Errorf("%d reads reached the file for prefetched regions", file.reads)
+	}
+
+	// Outside (or across) the regions, reads go to the file
+	if n, err := r.ReadAt(buf, 95); n != 10 || err != nil || buf[0] != 95 {
+		t.Errorf("ReadAt(95) = %d, %v, %v", n, err, buf)
+	}
+	if _, err := r.ReadAt(buf, 995); err != io.EOF {
+		t.Errorf("ReadAt past the end = %v, want EOF", err)
+	}
+	if file.reads != 2 {
+		t.Errorf("%d reads reached the file, want 2", file.reads)
+	}
+}
+
+// streamParser is a HeaderParser recording what it was given
+type streamParser struct {
+	got      int
+	maxBytes int
+}
+
+func (p *streamParser) CanHandle(ext string) bool { return ext == ".test" }
+func (p *streamParser) Name() string              { return "test" }
+
+func (p *streamParser) Parse(r io.Reader, maxBytes int) (*MediaMetadata, error) {
+	b, err := io.ReadAll(r)
+	p.got, p.maxBytes = len(b), maxBytes
+	return &MediaMetadata{Source: "test"}, err
+}
+
+func TestHeaderParserAdapter(t *testing.T) {
+	for _, size := range []int{512, 1024 * 1024} {
+		sp := &streamParser{}
+		p := FromHeaderParser(sp)
+		if p.Name() != "test" || !p.CanHandle(".test") {
+			t.Errorf("adapter doesn't forward Name/CanHandle")
+		}
+		want := min(size, headerLimit)
+		if regions := p.Regions(int64(size)); len(regions) != 1 || regions[0] != (Region{Offset: 0, Length: int64(want)}) {
+			t.Errorf("Regions(%d) = %v", size, regions)
+		}
+		if _, err := p.Parse(bytes.NewReader(make([]byte, size)), int64(size)); err != nil {
+			t.Fatal(err)
+		}
+		if sp.got != want || sp.maxBytes != want {
+			t.Errorf("size %d: parser read %d bytes (maxBytes %d), want %d", size, sp.got, sp.maxBytes, want)
+		}
+	}
+}
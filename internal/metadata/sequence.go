@@ -0,0 +1,46 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sequenceExts are formats written as one file per frame (ARRIRAW)
+var sequenceExts = map[string]bool{".ari": true}
+
+// frameNumber matches the frame number ending a sequence file name, with
+// its separator: "A001C001_230101_R1AB.0000123"
+var frameNumber = regexp.MustCompile(`^(.*?)[._-]?(\d+)$`)
+
+// IsSequenceExt reports whether files with this extension are single frames
+func IsSequenceExt(ext string) bool {
+	return sequenceExts[strings.ToLower(ext)]
+}
+
+// SequenceFrame splits the path of a sequence frame into the clip it
+// belongs to (same directory, frame number and extension dropped) and its
+// frame number: "A001/A001C001_230101_R1AB.0000123.ari" gives
+// "A001/A001C001_230101_R1AB", 123. ok is false for other files.
+func SequenceFrame(path string) (clip string, frame int, ok bool) {
+	ext := filepath.Ext(path)
+	if !IsSequenceExt(ext) {
+		return "", 0, false
+	}
+	m := frameNumber.FindStringSubmatch(strings.TrimSuffix(path, ext))
+	if m == nil {
+		return "", 0, false
+	}
+	frame, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, false
+	}
+	clip = m[1]
+	// Frames named by number only ("A001C001/0000123.ari") take the directory name
+	if clip == "" || os.IsPathSeparator(clip[len(clip)-1]) {
+		clip = filepath.Clean(clip)
+	}
+	return clip, frame, true
+}
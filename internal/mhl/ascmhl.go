@@ -0,0 +1,311 @@
+package mhl
+
+import (
+	"crypto/sha512"
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"loot/internal/config"
+	"loot/internal/offload"
+)
+
+// ASC MHL v2.0 layout constants
+const (
+	ASCMHLFolder    = "ascmhl"
+	ASCMHLChainFile = "ascmhl_chain.xml"
+
+	ascNamespace      = "urn:ASC:MHL:v2.0"
+	ascChainNamespace = "urn:ASC:MHL:DIRECTORY:v2.0"
+	ascDateFormat     = "2006-01-02T15:04:05-07:00"
+	ascFileDateFormat = "2006-01-02_150405Z"
+)
+
+// Hash actions as defined by the ASC MHL specification
+const (
+	ActionOriginal = "original"
+	ActionVerified = "verified"
+	ActionFailed   = "failed"
+)
+
+// ASCHashList is a single ASC MHL generation file
+type ASCHashList struct {
+	XMLName     xml.Name       `xml:"hashlist"`
+	Version     string         `xml:"version,attr"`
+	Xmlns       string         `xml:"xmlns,attr"`
+	CreatorInfo ASCCreatorInfo `xml:"creatorinfo"`
+	ProcessInfo ASCProcessInfo `xml:"processinfo"`
+	Hashes      []ASCHash      `xml:"hashes>hash"`
+}
+
+// ASCCreatorInfo describes who and what created a generation
+type ASCCreatorInfo struct {
+	CreationDate string  `xml:"creationdate"`
+	HostName     string  `xml:"hostname"`
+	Tool         ASCTool `xml:"tool"`
+	Location     string  `xml:"location,omitempty"`
+	Comment      string  `xml:"comment,omitempty"`
+}
+
+// ASCTool identifies the software that wrote the generation
+type ASCTool struct {
+	Version string `xml:"version,attr"`
+	Name    string `xml:",chardata"`
+}
+
+// ASCProcessInfo describes the kind of operation recorded
+type ASCProcessInfo struct {
+	Process string `xml:"process"`
+}
+
+// ASCHash is a per-file entry in a generation
+type ASCHash struct {
+	Path   ASCPath       `xml:"path"`
+	XXH64  *ASCHashValue `xml:"xxh64,omitempty"`
+	MD5    *ASCHashValue `xml:"md5,omitempty"`
+	SHA256 *ASCHashValue `xml:"sha256,omitempty"`
+}
+
+// ASCPath is the file path relative to the MHL root
+type ASCPath struct {
+	Size                 int64  `xml:"size,attr"`
+	LastModificationDate string `xml:"lastmodificationdate,attr,omitempty"`
+	Value                string `xml:",chardata"`
+}
+
+// ASCHashValue is a single hash with its action
+type ASCHashValue struct {
+	Action   string `xml:"action,attr"`
+	HashDate string `xml:"hashdate,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// ASCChain is the ascmhl_chain.xml manifest linking all generations
+type ASCChain struct {
+	XMLName   xml.Name        `xml:"ascmhldirectory"`
+	Xmlns     string          `xml:"xmlns,attr"`
+	HashLists []ASCChainEntry `xml:"hashlist"`
+}
+
+// ASCChainEntry references one generation file and its C4 id
+type ASCChainEntry struct {
+	SequenceNr int    `xml:"sequencenr,attr"`
+	Path       string `xml:"path"`
+	C4         string `xml:"c4"`
+}
+
+// GenerateASCMHL writes a new ASC MHL v2.0 generation into root/ascmhl and
+// appends it to the chain. files are those intact on root: callers leave out
+// copies that failed verification. Existing generations are kept; files
+// recorded with the same hash by a previous generation are marked verified,
+// new or changed ones original.
+func GenerateASCMHL(root string, files []offload.FileRes, cfg *config.Config) error {
+	dir := filepath.Join(root, ASCMHLFolder)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create ascmhl folder: %w", err)
+	}
+
+	chain, err := ReadASCChain(dir)
+	if err != nil {
+		return err
+	}
+
+	// Latest known hashes per path, used to derive actions
+	previous, err := readASCEntries(dir, chain)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	hashDate := now.Format(ascDateFormat)
+
+	list := ASCHashList{
+		Version:     "2.0",
+		Xmlns:       ascNamespace,
+		CreatorInfo: newCreatorInfo(cfg, hashDate),
+		ProcessInfo: ASCProcessInfo{Process: "transfer"},
+		Hashes:      make([]ASCHash, 0, len(files)),
+	}
+
+	for _, f := range files {
+		// ASC MHL only records content: links are left to the legacy MHL
+		if f.Link != "" {
+			continue
+		}
+		relPath := filepath.ToSlash(f.RelPath)
+		entry := ASCHash{
+			Path: ASCPath{
+				Size:                 f.Size,
+				LastModificationDate: f.ModTime.Format(ascDateFormat),
+				Value:                relPath,
+			},
+		}
+		prev := previous[relPath]
+
+		if f.Hash.XXHash64 != "" {
+			entry.XXH64 = &ASCHashValue{Action: action(prev.Hash.XXHash64, f.Hash.XXHash64), HashDate: hashDate, Value: f.Hash.XXHash64}
+		}
+		if f.Hash.MD5 != "" {
+			entry.MD5 = &ASCHashValue{Action: action(prev.Hash.MD5, f.Hash.MD5), HashDate: hashDate, Value: f.Hash.MD5}
+		}
+		if f.Hash.SHA256 != "" {
+			entry.SHA256 = &ASCHashValue{Action: action(prev.Hash.SHA256, f.Hash.SHA256), HashDate: hashDate, Value: f.Hash.SHA256}
+		}
+		list.Hashes = append(list.Hashes, entry)
+	}
+
+	output, err := xml.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ASC MHL: %w", err)
+	}
+	data := append([]byte(xml.Header), output...)
+
+	seq := len(chain.HashLists) + 1
+	name := fmt.Sprintf("%04d_%s_%s.mhl", seq, filepath.Base(root), now.UTC().Format(ascFileDateFormat))
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write ASC MHL generation: %w", err)
+	}
+
+	chain.HashLists = append(chain.HashLists, ASCChainEntry{
+		SequenceNr: seq,
+		Path:       name,
+		C4:         C4ID(data),
+	})
+	return writeASCChain(dir, chain)
+}
+
+// ReadASCChain loads the chain file from an ascmhl folder.
+// A missing chain is not an error and yields an empty chain.
+func ReadASCChain(dir string) (*ASCChain, error) {
+	chain := &ASCChain{Xmlns: ascChainNamespace}
+
+	data, err := os.ReadFile(filepath.Join(dir, ASCMHLChainFile))
+	if os.IsNotExist(err) {
+		return chain, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := xml.Unmarshal(data, chain); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ASCMHLChainFile, err)
+	}
+	chain.Xmlns = ascChainNamespace
+
+	sort.Slice(chain.HashLists, func(i, j int) bool {
+		return chain.HashLists[i].SequenceNr < chain.HashLists[j].SequenceNr
+	})
+	return chain, nil
+}
+
+// ReadASCHashList parses a single generation file
+func ReadASCHashList(path string) (*ASCHashList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list ASCHashList
+	if err := xml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return &list, nil
+}
+
+// readASCEntries merges all generations of a chain into the latest known
+// entry per path. Hashes recorded with a failed action (by other tools) are
+// ignored so the last good value is kept as reference.
+func readASCEntries(dir string, chain *ASCChain) (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+	for _, e := range chain.HashLists {
+		list, err := ReadASCHashList(filepath.Join(dir, e.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read generation %d: %w", e.SequenceNr, err)
+		}
+		// Later generations override earlier ones
+		for _, h := range list.Hashes {
+			entry := entries[h.Path.Value]
+			entry.Path = h.Path.Value
+			entry.Size = h.Path.Size
+			if h.XXH64 != nil && h.XXH64.Action != ActionFailed {
+				entry.Hash.XXHash64 = h.XXH64.Value
+			}
+			if h.MD5 != nil && h.MD5.Action != ActionFailed {
+				entry.Hash.MD5 = h.MD5.Value
+			}
+			if h.SHA256 != nil && h.SHA256.Action != ActionFailed {
+				entry.Hash.SHA256 = h.SHA256.Value
+			}
+			entries[h.Path.Value] = entry
+		}
+	}
+	return entries, nil
+}
+
+// action is verified when a previous generation recorded the same hash.
+// A different one is new content this transfer copied and verified, so it
+// is original like a new file.
+func action(previous, current string) string {
+	if previous != "" && previous == current {
+		return ActionVerified
+	}
+	return ActionOriginal
+}
+
+func newCreatorInfo(cfg *config.Config, date string) ASCCreatorInfo {
+	host, _ := os.Hostname()
+
+	version := cfg.Version
+	if version == "" {
+		version = "dev"
+	}
+
+	var comment []string
+	if cfg.JobName != "" {
+		comment = append(comment, "Job: "+cfg.JobName)
+	}
+	if cfg.Camera != "" {
+		comment = append(comment, "Camera: "+cfg.Camera)
+	}
+	if cfg.Reel != "" {
+		comment = append(comment, "Reel: "+cfg.Reel)
+	}
+
+	return ASCCreatorInfo{
+		CreationDate: date,
+		HostName:     host,
+		Tool:         ASCTool{Name: "LOOT", Version: version},
+		Comment:      strings.Join(comment, ", "),
+	}
+}
+
+func writeASCChain(dir string, chain *ASCChain) error {
+	output, err := xml.MarshalIndent(chain, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ASC MHL chain: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, ASCMHLChainFile), append([]byte(xml.Header), output...), 0644)
+}
+
+const c4Charset = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// C4ID returns the SMPTE ST 2114 C4 id (base58 SHA-512) used by the chain file
+func C4ID(data []byte) string {
+	sum := sha512.Sum512(data)
+	n := new(big.Int).SetBytes(sum[:])
+
+	base := big.NewInt(58)
+	mod := new(big.Int)
+	encoded := make([]byte, 88)
+	for i := range encoded {
+		encoded[i] = '1'
+	}
+	for i := len(encoded) - 1; i >= 0 && n.Sign() > 0; i-- {
+		n.DivMod(n, base, mod)
+		encoded[i] = c4Charset[mod.Int64()]
+	}
+	return "c4" + string(encoded)
+}
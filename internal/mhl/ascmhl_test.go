@@ -0,0 +1,118 @@
+package mhl
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"loot/internal/config"
+	"loot/internal/hash"
+	"loot/internal/offload"
+)
+
+func TestGenerateASCMHL_Generations(t *testing.T) {
+	root, err := ioutil.TempDir("", "loot_ascmhl_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	cfg := config.DefaultConfig()
+	cfg.JobName = "Day01"
+	cfg.Camera = "A"
+	cfg.Reel = "001"
+
+	files := []offload.FileRes{
+		{RelPath: "clip.mov", Size: 5, ModTime: time.Now(), Hash: hash.HashResult{XXHash64: "0ea03b369a463d9d"}},
+	}
+
+	if err := GenerateASCMHL(root, files, cfg); err != nil {
+		t.Fatalf("first generation failed: %v", err)
+	}
+
+	// Second run: same file unchanged plus one new file
+	files = append(files, offload.FileRes{RelPath: "sub/new.mov", Size: 3, ModTime: time.Now(), Hash: hash.HashResult{XXHash64: "1111111111111111"}})
+	if err := GenerateASCMHL(root, files, cfg); err != nil {
+		t.Fatalf("second generation failed: %v", err)
+	}
+
+	dir := filepath.Join(root, ASCMHLFolder)
+	chain, err := ReadASCChain(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain.HashLists) != 2 {
+		t.Fatalf("expected 2 generations in chain, got %d", len(chain.HashLists))
+	}
+	if !strings.HasPrefix(chain.HashLists[1].Path, "0002_") {
+		t.Errorf("unexpected generation name %s", chain.HashLists[1].Path)
+	}
+
+	// C4 in chain must match file content
+	data, err := ioutil.ReadFile(filepath.Join(dir, chain.HashLists[0].Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := C4ID(data); got != chain.HashLists[0].C4 {
+		t.Errorf("chain C4 mismatch: %s vs %s", chain.HashLists[0].C4, got)
+	}
+
+	list, err := ReadASCHashList(filepath.Join(dir, chain.HashLists[1].Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(list.CreatorInfo.Comment, "Reel: 001") {
+		t.Errorf("creator comment missing reel: %q", list.CreatorInfo.Comment)
+	}
+
+	actions := map[string]string{}
+	for _, h := range list.Hashes {
+		actions[h.Path.Value] = h.XXH64.Action
+	}
+	if actions["clip.mov"] != ActionVerified {
+		t.Errorf("clip.mov action = %s, want verified", actions["clip.mov"])
+	}
+	if actions["sub/new.mov"] != ActionOriginal {
+		t.Errorf("sub/new.mov action = %s, want original", actions["sub/new.mov"])
+	}
+
+	// Third run: clip.mov was replaced on the source and copied again
+	files[0].Hash.XXHash64 = "2222222222222222"
+	if err := GenerateASCMHL(root, files, cfg); err != nil {
+		t.Fatalf("third generation failed: %v", err)
+	}
+	chain, err = ReadASCChain(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err = ReadASCHashList(filepath.Join(dir, chain.HashLists[2].Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range list.Hashes {
+		if h.Path.Value == "clip.mov" && h.XXH64.Action != ActionOriginal {
+			t.Errorf("changed clip.mov action = %s, want original", h.XXH64.Action)
+		}
+	}
+	m, err := LoadManifest(root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Entries["clip.mov"].Hash.XXHash64; got != "2222222222222222" {
+		t.Errorf("manifest hash of changed clip.mov = %s, want the new one", got)
+	}
+}
+
+func TestC4ID(t *testing.T) {
+	// Reference value from the C4 specification (empty input)
+	id := C4ID([]byte(""))
+	if len(id) != 90 || !strings.HasPrefix(id, "c4") {
+		t.Fatalf("malformed C4 id: %s", id)
+	}
+	want := "c459dsjfscH38cYeXXYogktxf4Cd9ibshE3BHUo6a58hBXmRQdZrAkZzsWcbWtDg5oQstpDuni4Hirj75GEmTc1sFT"
+	if id != want {
+		t.Errorf("C4ID(\"\") = %s, want %s", id, want)
+	}
+}
@@ -24,6 +24,8 @@ type Hash struct {
 	XXHash64     string `xml:"xxhash64,omitempty"`
 	MD5          string `xml:"md5,omitempty"`
 	SHA1         string `xml:"sha1,omitempty"`
+	SHA256       string `xml:"sha256,omitempty"`
+	LinkTarget   string `xml:"linktarget,omitempty"` // Symlink copied as a link: no content, no hash
 }
 
 // GenerateMHL creates an MHL file for the offload operation
@@ -50,6 +52,8 @@ func GenerateMHL(path string, files []offload.FileRes) error {
 			LastModified: f.ModTime.Format(time.RFC3339),
 			XXHash64:     f.Hash.XXHash64,
 			MD5:          f.Hash.MD5,
+			SHA256:       f.Hash.SHA256,
+			LinkTarget:   f.Link,
 		}
 	}
 
@@ -61,3 +65,16 @@ func GenerateMHL(path string, files []offload.FileRes) error {
 	header := []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
 	return os.WriteFile(path, append(header, output...), 0644)
 }
+
+// ReadMHL parses an MHL 1.0 file written by GenerateMHL
+func ReadMHL(path string) (*MHL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m MHL
+	if err := xml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse MHL: %w", err)
+	}
+	return &m, nil
+}
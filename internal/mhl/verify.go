@@ -0,0 +1,248 @@
+package mhl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"loot/internal/config"
+	"loot/internal/hash"
+	"loot/internal/offload"
+	"loot/internal/output"
+)
+
+// Entry is a single file recorded in a hash list
+type Entry struct {
+	Path string // Relative, slash separated
+	Size int64
+	Hash hash.HashResult
+	Link string // Target of a symlink, which has no hash
+}
+
+// Manifest is the merged view of every hash list found for a root.
+// ASC MHL generations take precedence over the legacy MHL 1.0 file.
+type Manifest struct {
+	Sources []string
+	Entries map[string]Entry
+}
+
+// LoadManifest reads the legacy MHL (mhlPath, or root+".mhl" if empty)
+// and the ASC MHL history in root/ascmhl if present.
+func LoadManifest(root, mhlPath string) (*Manifest, error) {
+	m := &Manifest{Entries: make(map[string]Entry)}
+
+	explicit := mhlPath != ""
+	if !explicit {
+		mhlPath = filepath.Clean(root) + ".mhl"
+	}
+
+	legacy, err := ReadMHL(mhlPath)
+	switch {
+	case err == nil:
+		m.Sources = append(m.Sources, mhlPath)
+		for _, h := range legacy.Hashes {
+			path := filepath.ToSlash(h.File)
+			m.Entries[path] = Entry{
+				Path: path,
+				Size: h.Size,
+				Hash: hash.HashResult{XXHash64: h.XXHash64, MD5: h.MD5, SHA256: h.SHA256},
+				Link: h.LinkTarget,
+			}
+		}
+	case explicit || !os.IsNotExist(err):
+		return nil, err
+	}
+
+	ascDir := filepath.Join(root, ASCMHLFolder)
+	chain, err := ReadASCChain(ascDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain.HashLists) > 0 {
+		entries, err := readASCEntries(ascDir, chain)
+		if err != nil {
+			return nil, err
+		}
+		m.Sources = append(m.Sources, filepath.Join(ascDir, ASCMHLChainFile))
+		for path, e := range entries {
+			m.Entries[path] = e
+		}
+	}
+
+	if len(m.Sources) == 0 {
+		return nil, fmt.Errorf("no MHL found for %s", root)
+	}
+	return m, nil
+}
+
+// VerifyRoot re-hashes every file referenced by the manifest under root
+// and reports missing, extra, size-changed and mismatched files.
+func VerifyRoot(root string, m *Manifest) (*output.VerifyResult, error) {
+	start := time.Now()
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &output.VerifyResult{
+		Timestamp: start,
+		Root:      root,
+		Manifests: m.Sources,
+	}
+
+	// A single file destination is checked against its only entry
+	if !info.IsDir() {
+		if len(m.Entries) != 1 {
+			return nil, fmt.Errorf("%s is a file but the MHL lists %d entries", root, len(m.Entries))
+		}
+		for _, e := range m.Entries {
+			result.Files = append(result.Files, checkEntry(root, e))
+		}
+		result.Finish(time.Since(start))
+		return result, nil
+	}
+
+	paths := make([]string, 0, len(m.Entries))
+	for path := range m.Entries {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		result.Files = append(result.Files, checkEntry(filepath.Join(root, filepath.FromSlash(path)), m.Entries[path]))
+	}
+
+	// Anything on disk that no hash list knows about
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, _ := filepath.Rel(root, path)
+		// Partials are skipped when copying, but one left on a destination is
+		// an interrupted copy that nothing cleaned up: report it
+		if !info.IsDir() && strings.HasSuffix(info.Name(), offload.PartialSuffix) {
+			result.Files = append(result.Files, output.FileCheck{
+				Path:       filepath.ToSlash(relPath),
+				Status:     offload.VerifyExtra,
+				ActualSize: info.Size(),
+				Error:      "interrupted copy leftover",
+			})
+			return nil
+		}
+		if offload.ShouldSkip(info.Name()) || relPath == ASCMHLFolder {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		// Links are only recorded by the legacy MHL (ASC MHL has no way to),
+		// so one no hash list knows about isn't an extra file
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		if _, ok := m.Entries[filepath.ToSlash(relPath)]; !ok {
+			result.Files = append(result.Files, output.FileCheck{
+				Path:       filepath.ToSlash(relPath),
+				Status:     offload.VerifyExtra,
+				ActualSize: info.Size(),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Finish(time.Since(start))
+	return result, nil
+}
+
+func checkEntry(path string, e Entry) output.FileCheck {
+	check := output.FileCheck{
+		Path:         e.Path,
+		ExpectedSize: e.Size,
+		Expected:     e.Hash,
+	}
+	if e.Link != "" {
+		return checkLink(path, e.Link, check)
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		check.Status = offload.VerifyMissing
+		return check
+	}
+	if err != nil {
+		check.Status = offload.VerifyUnreadable
+		check.Error = err.Error()
+		return check
+	}
+	check.ActualSize = info.Size()
+	if info.Size() != e.Size {
+		check.Status = offload.VerifySizeChanged
+		return check
+	}
+
+	algos := recordedAlgorithms(e.Hash)
+	if len(algos) == 0 {
+		check.Status = offload.VerifyUnreadable
+		check.Error = "no supported hash recorded"
+		return check
+	}
+
+	actual, err := hash.CalculateFileHash(path, algos...)
+	if err != nil {
+		check.Status = offload.VerifyUnreadable
+		check.Error = err.Error()
+		return check
+	}
+	check.Actual = actual
+
+	for _, algo := range algos {
+		if !strings.EqualFold(e.Hash.GetPrimary(algo), actual.GetPrimary(algo)) {
+			check.Status = offload.VerifyMismatch
+			return check
+		}
+	}
+	check.Status = offload.VerifyOK
+	return check
+}
+
+// checkLink compares the target of the symlink at path with the recorded one
+func checkLink(path, target string, check output.FileCheck) output.FileCheck {
+	got, err := os.Readlink(path)
+	switch {
+	case os.IsNotExist(err):
+		check.Status = offload.VerifyMissing
+	case err != nil:
+		check.Status = offload.VerifyMismatch
+		check.Error = fmt.Sprintf("not a symlink, expected link to %s", target)
+	case got != target:
+		check.Status = offload.VerifyMismatch
+		check.Error = fmt.Sprintf("link to %s, expected %s", got, target)
+	default:
+		check.Status = offload.VerifyOK
+	}
+	return check
+}
+
+func recordedAlgorithms(h hash.HashResult) []config.HashAlgorithm {
+	var algos []config.HashAlgorithm
+	if h.XXHash64 != "" {
+		algos = append(algos, config.AlgoXXHash64)
+	}
+	if h.MD5 != "" {
+		algos = append(algos, config.AlgoMD5)
+	}
+	if h.SHA256 != "" {
+		algos = append(algos, config.AlgoSHA256)
+	}
+	return algos
+}
@@ -0,0 +1,161 @@
+package mhl
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"loot/internal/config"
+	"loot/internal/hash"
+	"loot/internal/offload"
+)
+
+func TestVerifyRoot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_verify_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	root := filepath.Join(dir, "A001")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(name, content string) offload.FileRes {
+		path := filepath.Join(root, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		h, err := hash.CalculateFileHash(path, config.AlgoXXHash64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, _ := os.Stat(path)
+		return offload.FileRes{RelPath: name, Size: info.Size(), ModTime: info.ModTime(), Hash: h}
+	}
+
+	files := []offload.FileRes{
+		write("ok.mov", "intact"),
+		write("gone.mov", "deleted later"),
+		write("bad.mov", "abc"),
+		write("short.mov", "truncated later"),
+	}
+	if err := GenerateMHL(root+".mhl", files); err != nil {
+		t.Fatal(err)
+	}
+
+	// Damage the destination
+	os.Remove(filepath.Join(root, "gone.mov"))
+	ioutil.WriteFile(filepath.Join(root, "bad.mov"), []byte("xyz"), 0644)
+	ioutil.WriteFile(filepath.Join(root, "short.mov"), []byte("trunc"), 0644)
+	ioutil.WriteFile(filepath.Join(root, "extra.mov"), []byte("new"), 0644)
+	ioutil.WriteFile(offload.PartialPath(filepath.Join(root, "lost.mov")), []byte("half"), 0644)
+
+	manifest, err := LoadManifest(root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := VerifyRoot(root, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]offload.VerifyStatus{
+		"ok.mov":    offload.VerifyOK,
+		"gone.mov":  offload.VerifyMissing,
+		"bad.mov":   offload.VerifyMismatch,
+		"short.mov": offload.VerifySizeChanged,
+		"extra.mov": offload.VerifyExtra,
+
+		".lost.mov" + offload.PartialSuffix: offload.VerifyExtra,
+	}
+	for _, f := range result.Files {
+		if want[f.Path] != f.Status {
+			t.Errorf("%s: status %s, want %s", f.Path, f.Status, want[f.Path])
+		}
+	}
+	if result.Status != "failed" || result.TotalFiles != len(want) {
+		t.Errorf("unexpected result: status=%s total=%d", result.Status, result.TotalFiles)
+	}
+}
+
+func TestVerifyRootAfterCopyWithLinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_verify_links_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src, dst := filepath.Join(dir, "card"), filepath.Join(dir, "A001")
+	if err := os.MkdirAll(filepath.Join(src, "A001"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "A001", "clip.mov"), []byte("clip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("A001/clip.mov", filepath.Join(src, "latest.mov")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("A001", filepath.Join(src, "current")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Default policy: links are recreated as links
+	cfg := config.DefaultConfig()
+	cfg.MetadataMode = "off"
+	o := offload.NewOffloaderWithConfig(cfg, src, dst)
+	if err := o.Copy(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := o.Verify(context.Background(), nil); !ok || err != nil {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+	if err := GenerateMHL(dst+".mhl", o.Files); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateASCMHL(dst, o.Files, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func() map[string]offload.VerifyStatus {
+		t.Helper()
+		manifest, err := LoadManifest(dst, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := VerifyRoot(dst, manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]offload.VerifyStatus)
+		for _, f := range result.Files {
+			got[f.Path] = f.Status
+		}
+		return got
+	}
+
+	got := verify()
+	want := map[string]offload.VerifyStatus{
+		"A001/clip.mov": offload.VerifyOK,
+		"latest.mov":    offload.VerifyOK,
+		"current":       offload.VerifyOK,
+	}
+	if len(got) != len(want) {
+		t.Errorf("checked %v, want %v", got, want)
+	}
+	for path, status := range want {
+		if got[path] != status {
+			t.Errorf("%s: status %s, want %s", path, got[path], status)
+		}
+	}
+
+	// A link pointing elsewhere is a mismatch
+	os.Remove(filepath.Join(dst, "latest.mov"))
+	if err := os.Symlink("A001/other.mov", filepath.Join(dst, "latest.mov")); err != nil {
+		t.Fatal(err)
+	}
+	if status := verify()["latest.mov"]; status != offload.VerifyMismatch {
+		t.Errorf("retargeted link: status %s, want %s", status, offload.VerifyMismatch)
+	}
+}
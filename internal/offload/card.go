@@ -0,0 +1,265 @@
+package offload
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"loot/internal/config"
+	"loot/internal/metadata"
+)
+
+// CardVendor is the camera family that wrote a card
+type CardVendor string
+
+const (
+	VendorRED        CardVendor = "RED"
+	VendorARRI       CardVendor = "ARRI"
+	VendorSony       CardVendor = "Sony"
+	VendorBlackmagic CardVendor = "Blackmagic"
+	VendorCanon      CardVendor = "Canon"
+)
+
+// Card describes a camera card layout found at a path
+type Card struct {
+	Vendor CardVendor
+	Format string // Recording format, e.g. "R3D", "ARRIRAW", "XAVC"
+	Root   string // Card root
+	Name   string // Card / roll name as written by the camera, e.g. "A001R1AB"
+	Camera string // Camera letter from the roll name, e.g. "A"
+	Reel   string // Reel from the roll name, e.g. "A001"
+	Clips  int
+
+	// ClipDirs are the folders holding the clips
+	ClipDirs []string
+	// ClipExts are the media extensions of this format (lower case)
+	ClipExts []string
+	// Sidecars are suffixes every clip is expected to come with
+	// (e.g. "M01.XML" for Sony), matched case-insensitively
+	Sidecars []string
+}
+
+// String is a short label, e.g. "RED R3D A001 (12 clips)"
+func (c *Card) String() string {
+	s := string(c.Vendor) + " " + c.Format
+	if c.Reel != "" {
+		s += " " + c.Reel
+	} else if c.Name != "" {
+		s += " " + c.Name
+	}
+	if c.Clips == 1 {
+		return s + " (1 clip)"
+	}
+	return fmt.Sprintf("%s (%d clips)", s, c.Clips)
+}
+
+// Card folder naming; camera and reel come from metadata.RollFromName
+var (
+	arriRoll  = regexp.MustCompile(`^[A-Z]\d{3}R[0-9A-Z]{2,4}$`)
+	canonClip = regexp.MustCompile(`^CLIPS\d{3}$`)
+)
+
+// DetectCard recognises a camera card layout at root. It only looks at the
+// root and one level below, so it's cheap enough for volume listings.
+// It returns nil when root doesn't look like a camera card.
+func DetectCard(root string) *Card {
+	for _, detect := range []func(string) *Card{
+		detectSony, detectCanon, detectRED, detectARRI, detectBlackmagic,
+	} {
+		if c := detect(root); c != nil {
+			c.Root = root
+			if c.Name == "" {
+				c.Name = filepath.Base(root)
+			}
+			if c.Camera == "" && c.Reel == "" {
+				c.Camera, c.Reel = metadata.RollFromName(c.Name)
+			}
+			return c
+		}
+	}
+	return nil
+}
+
+// PrefillFromCard fills an empty Camera and Reel in cfg from the card at
+// cfg.Source. It returns the card, or nil if the source isn't one.
+func PrefillFromCard(cfg *config.Config) *Card {
+	card := DetectCard(cfg.Source)
+	if card == nil {
+		return nil
+	}
+	if cfg.Camera == "" {
+		cfg.Camera = card.Camera
+	}
+	if cfg.Reel == "" {
+		cfg.Reel = card.Reel
+	}
+	return card
+}
+
+// Sony XAVC / X-OCN: XDROOT/Clip, or PRIVATE/M4ROOT/CLIP on smaller bodies
+func detectSony(root string) *Card {
+	for _, dir := range []string{
+		filepath.Join(root, "XDROOT", "Clip"),
+		filepath.Join(root, "PRIVATE", "M4ROOT", "CLIP"),
+	} {
+		if !isDir(dir) {
+			continue
+		}
+		exts := []string{".mxf", ".mp4"}
+		c := &Card{Vendor: VendorSony, Format: "XAVC", ClipDirs: []string{dir}, ClipExts: exts, Sidecars: []string{"M01.XML"}}
+		names := filesWithExt(dir, exts...)
+		c.Clips = len(names)
+		if len(names) > 0 {
+			c.Camera, c.Reel = metadata.RollFromName(names[0])
+		}
+		return c
+	}
+	return nil
+}
+
+// Canon: CONTENTS/CLIPS001 with Cinema RAW Light (.CRM) or XF-AVC/MP4 clips
+func detectCanon(root string) *Card {
+	contents := filepath.Join(root, "CONTENTS")
+	dirs := subdirs(contents, func(name string) bool { return canonClip.MatchString(strings.ToUpper(name)) })
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	c := &Card{Vendor: VendorCanon, Format: "XF-AVC", ClipDirs: dirs, ClipExts: []string{".crm", ".mxf", ".mp4"}, Sidecars: []string{".XML"}}
+	var names []string
+	for _, d := range dirs {
+		names = append(names, filesWithExt(d, c.ClipExts...)...)
+	}
+	for _, n := range names {
+		if strings.EqualFold(filepath.Ext(n), ".crm") {
+			c.Format = "Cinema RAW Light"
+			break
+		}
+	}
+	c.Clips = len(names)
+	if len(names) > 0 {
+		c.Camera, c.Reel = metadata.RollFromName(names[0])
+	}
+	return c
+}
+
+// RED: <reel>.RDM folders holding one <clip>.RDC folder per clip. Spanned
+// clips are split into numbered .R3D segments inside the .RDC.
+func detectRED(root string) *Card {
+	isRDM := func(name string) bool { return strings.EqualFold(filepath.Ext(name), ".rdm") }
+
+	rdms := subdirs(root, isRDM)
+	if len(rdms) == 0 && strings.EqualFold(filepath.Ext(root), ".rdm") {
+		rdms = []string{root}
+	}
+	if len(rdms) == 0 {
+		return nil
+	}
+
+	c := &Card{Vendor: VendorRED, Format: "R3D", ClipExts: []string{".r3d"}, Sidecars: []string{".RMD"}}
+	c.Name = strings.TrimSuffix(filepath.Base(rdms[0]), filepath.Ext(rdms[0]))
+	for _, rdm := range rdms {
+		rdcs := subdirs(rdm, func(name string) bool { return strings.EqualFold(filepath.Ext(name), ".rdc") })
+		c.ClipDirs = append(c.ClipDirs, rdcs...)
+		c.Clips += len(rdcs)
+	}
+	return c
+}
+
+// ARRI: a roll folder (A001R1AB) of MXF clips or ARRIRAW .ari frame folders
+func detectARRI(root string) *Card {
+	rolls := subdirs(root, func(name string) bool { return arriRoll.MatchString(strings.ToUpper(name)) })
+	if arriRoll.MatchString(strings.ToUpper(filepath.Base(root))) {
+		rolls = append(rolls, root)
+	}
+	if len(rolls) == 0 {
+		return nil
+	}
+
+	c := &Card{Vendor: VendorARRI, Format: "MXF", Name: filepath.Base(rolls[0]), ClipExts: []string{".mxf", ".mov", ".ari"}}
+	for _, roll := range rolls {
+		// ALEXA 35 / Mini LF: clips directly in the roll folder
+		if n := len(filesWithExt(roll, ".mxf", ".mov")); n > 0 {
+			c.ClipDirs = append(c.ClipDirs, roll)
+			c.Clips += n
+		}
+		// ARRIRAW .ari: one folder of frames per clip
+		for _, clip := range subdirs(roll, nil) {
+			if len(filesWithExt(clip, ".ari")) > 0 {
+				c.Format = "ARRIRAW"
+				c.ClipDirs = append(c.ClipDirs, clip)
+				c.Clips++
+			}
+		}
+	}
+	if c.Clips == 0 {
+		return nil
+	}
+	return c
+}
+
+// Blackmagic: .braw clips at the card root or one folder down
+func detectBlackmagic(root string) *Card {
+	c := &Card{Vendor: VendorBlackmagic, Format: "BRAW", ClipExts: []string{".braw"}}
+	for _, dir := range append([]string{root}, subdirs(root, nil)...) {
+		if n := len(filesWithExt(dir, ".braw")); n > 0 {
+			c.ClipDirs = append(c.ClipDirs, dir)
+			c.Clips += n
+		}
+	}
+	if c.Clips == 0 {
+		return nil
+	}
+	c.Camera, c.Reel = metadata.RollFromName(filesWithExt(c.ClipDirs[0], ".braw")[0])
+	return c
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// subdirs lists the non-hidden sub-directories of dir accepted by match (all if nil)
+func subdirs(dir string, match func(name string) bool) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") || ShouldSkip(e.Name()) {
+			continue
+		}
+		if match == nil || match(e.Name()) {
+			dirs = append(dirs, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// filesWithExt lists the names of regular files in dir with one of exts
+func filesWithExt(dir string, exts ...string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		for _, want := range exts {
+			if ext == want {
+				names = append(names, e.Name())
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
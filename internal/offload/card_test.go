@@ -0,0 +1,193 @@
+package offload
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"loot/internal/config"
+)
+
+// makeCard creates empty files (and their folders) under a fresh temp dir
+func makeCard(t *testing.T, files ...string) string {
+	t.Helper()
+	root, err := ioutil.TempDir("", "loot_card_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestDetectCard(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  []string
+		vendor CardVendor
+		format string
+		reel   string
+		clips  int
+	}{
+		{
+			name: "RED",
+			files: []string{
+				"A002_0412AB.RDM/A002_C001_0412XY.RDC/A002_C001_0412XY_001.R3D",
+				"A002_0412AB.RDM/A002_C001_0412XY.RDC/A002_C001_0412XY.RMD",
+				"A002_0412AB.RDM/A002_C002_0412XY.RDC/A002_C002_0412XY_001.R3D",
+			},
+			vendor: VendorRED, format: "R3D", reel: "A002", clips: 2,
+		},
+		{
+			name: "ARRI MXF",
+			files: []string{
+				"B003R2CD/B003C001_240307_R2CD.mxf",
+				"B003R2CD/B003C002_240307_R2CD.mxf",
+				"B003R2CD/B003C003_240307_R2CD.mxf",
+			},
+			vendor: VendorARRI, format: "MXF", reel: "B003", clips: 3,
+		},
+		{
+			name: "ARRIRAW",
+			files: []string{
+				"A010R1AB/A010C001_240307_R1AB/A010C001_240307_R1AB.0000001.ari",
+				"A010R1AB/A010C001_240307_R1AB/A010C001_240307_R1AB.0000002.ari",
+			},
+			vendor: VendorARRI, format: "ARRIRAW", reel: "A010", clips: 1,
+		},
+		{
+			name: "Sony",
+			files: []string{
+				"XDROOT/Clip/C005C001_240307.MXF",
+				"XDROOT/Clip/C005C001_240307M01.XML",
+			},
+			vendor: VendorSony, format: "XAVC", reel: "C005", clips: 1,
+		},
+		{
+			name:   "Canon RAW",
+			files:  []string{"CONTENTS/CLIPS001/D001C001_240307.CRM", "CONTENTS/CLIPS001/D001C001_240307.XML"},
+			vendor: VendorCanon, format: "Cinema RAW Light", reel: "D001", clips: 1,
+		},
+		{
+			name:   "Blackmagic",
+			files:  []string{"A001_03071230_C001.braw", "A001_03071230_C002.braw"},
+			vendor: VendorBlackmagic, format: "BRAW", reel: "A001", clips: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		card := DetectCard(makeCard(t, tt.files...))
+		if card == nil {
+			t.Errorf("%s: card not detected", tt.name)
+			continue
+		}
+		if card.Vendor != tt.vendor || card.Format != tt.format || card.Reel != tt.reel || card.Clips != tt.clips {
+			t.Errorf("%s: got %s %s reel=%q clips=%d, want %s %s reel=%q clips=%d", tt.name,
+				card.Vendor, card.Format, card.Reel, card.Clips, tt.vendor, tt.format, tt.reel, tt.clips)
+		}
+	}
+
+	if card := DetectCard(makeCard(t, "DCIM/100CANON/IMG_0001.JPG", "notes.txt")); card != nil {
+		t.Errorf("plain folder detected as %s", card)
+	}
+}
+
+func TestPrefillFromCard(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Source = makeCard(t, "XDROOT/Clip/C005C001_240307.MXF")
+	cfg.Reel = "C099"
+
+	if card := PrefillFromCard(cfg); card == nil {
+		t.Fatal("card not detected")
+	}
+	if cfg.Camera != "C" {
+		t.Errorf("Camera = %q, want C from the clip name", cfg.Camera)
+	}
+	if cfg.Reel != "C099" {
+		t.Errorf("Reel = %q, an existing value must be kept", cfg.Reel)
+	}
+}
+
+func TestCheckStructure(t *testing.T) {
+	root := makeCard(t,
+		// Segment _002 is missing, and so is the RMD
+		"A002_0412AB.RDM/A002_C001_0412XY.RDC/A002_C001_0412XY_001.R3D",
+		"A002_0412AB.RDM/A002_C001_0412XY.RDC/A002_C001_0412XY_003.R3D",
+		// Complete clip
+		"A002_0412AB.RDM/A002_C002_0412XY.RDC/A002_C002_0412XY_001.R3D",
+		"A002_0412AB.RDM/A002_C002_0412XY.RDC/A002_C002_0412XY.RMD",
+	)
+	empty := filepath.Join(root, "A002_0412AB.RDM", "A002_C002_0412XY.RDC", "A002_C002_0412XY_002.R3D")
+	if err := ioutil.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	o := NewOffloader(root, makeCard(t))
+	res, err := o.DryRun()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Card == nil || res.Card.Vendor != VendorRED {
+		t.Fatalf("card = %v, want RED", res.Card)
+	}
+
+	got := map[StructureIssueKind][]string{}
+	for _, issue := range res.Issues {
+		got[issue.Kind] = append(got[issue.Kind], filepath.Base(issue.Path))
+	}
+	want := map[StructureIssueKind][]string{
+		IssueZeroByte:       {"A002_C002_0412XY_002.R3D"},
+		IssueMissingSegment: {"A002_C001_0412XY_002.R3D"},
+		IssueMissingSidecar: {"A002_C001_0412XY.RMD"},
+	}
+	for kind, paths := range want {
+		if len(got[kind]) != 1 || got[kind][0] != paths[0] {
+			t.Errorf("%s: got %v, want %v", kind, got[kind], paths)
+		}
+	}
+	if len(res.Issues) != 3 {
+		t.Errorf("issues = %v, want 3", res.Issues)
+	}
+}
+
+func TestCheckStructureSidecars(t *testing.T) {
+	root := makeCard(t,
+		"XDROOT/Clip/C005C001_240307.MXF",
+		"XDROOT/Clip/C005C001_240307M01.XML",
+		"XDROOT/Clip/C005C002_240307.MXF",
+		"XDROOT/Clip/C005C003_240307M01.XML",
+	)
+	card := DetectCard(root)
+	issues := CheckStructure(root, card, nil)
+	if len(issues) != 2 {
+		t.Fatalf("issues = %v, want 2", issues)
+	}
+	if issues[0].Kind != IssueMissingSidecar || filepath.Base(issues[0].Path) != "C005C002_240307M01.XML" {
+		t.Errorf("issues[0] = %s", issues[0])
+	}
+	if issues[1].Kind != IssueOrphanedSidecar || filepath.Base(issues[1].Path) != "C005C003_240307M01.XML" {
+		t.Errorf("issues[1] = %s", issues[1])
+	}
+}
+
+func TestCheckStructureZeroByteOffCard(t *testing.T) {
+	files := []FileRes{
+		{RelPath: filepath.Join("project", "notes.txt")},
+		{RelPath: filepath.Join("project", ".keep")},
+		{RelPath: filepath.Join("project", "interview.MOV")},
+		{RelPath: filepath.Join("project", "edit.mov"), Size: 42},
+	}
+	issues := CheckStructure("/src", nil, files)
+	if len(issues) != 1 || issues[0].Kind != IssueZeroByte || filepath.Base(issues[0].Path) != "interview.MOV" {
+		t.Errorf("issues = %v, want only the empty interview.MOV", issues)
+	}
+}
@@ -0,0 +1,154 @@
+package offload
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"loot/internal/config"
+)
+
+// Exclusion is a source entry left out of a job
+type Exclusion struct {
+	RelPath string `json:"path"`
+	Dir     bool   `json:"dir,omitempty"` // The whole folder was skipped
+	Size    int64  `json:"size,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// rule is one include/exclude pattern:
+//
+//	*.tmp        glob on the file or folder name
+//	CLIPS/*.xml  glob on the slash separated path relative to the source
+//	re:_proxy\.  regular expression on the relative path
+type rule struct {
+	pattern string
+	reason  string
+	re      *regexp.Regexp
+}
+
+func newRule(pattern, reason string) (rule, error) {
+	r := rule{pattern: pattern, reason: reason}
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return r, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		r.re = re
+		return r, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return r, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return r, nil
+}
+
+func (r rule) match(relPath string) bool {
+	if r.re != nil {
+		return r.re.MatchString(relPath)
+	}
+	name := relPath
+	if !strings.Contains(r.pattern, "/") {
+		name = path.Base(relPath)
+	}
+	ok, _ := path.Match(r.pattern, name)
+	return ok
+}
+
+// DefaultExcludes are the system files each OS leaves on removable media.
+// They all apply on every OS, since cards move between machines.
+var DefaultExcludes = map[string][]struct{ Pattern, Reason string }{
+	"darwin": {
+		{".DS_Store", "macOS Finder metadata"},
+		{".Spotlight-V100", "macOS Spotlight index"},
+		{".fseventsd", "macOS file system events"},
+		{".Trashes", "macOS trash"},
+		{".TemporaryItems", "macOS temporary items"},
+		{".DocumentRevisions-V100", "macOS document versions"},
+		{"._*", "AppleDouble resource fork"},
+	},
+	"windows": {
+		{"Thumbs.db", "Windows thumbnail cache"},
+		{"desktop.ini", "Windows folder settings"},
+		{"$RECYCLE.BIN", "Windows recycle bin"},
+		{"System Volume Information", "Windows system folder"},
+	},
+	"linux": {
+		{".Trash-*", "Linux trash"},
+	},
+}
+
+// partialRule is always on: leftovers of an interrupted copy are never real media
+var partialRule = rule{pattern: "*" + PartialSuffix, reason: "interrupted copy leftover"}
+
+// Filter decides which source entries a job copies and verifies.
+// Excludes win over includes; include rules only apply to files.
+type Filter struct {
+	include []rule
+	exclude []rule
+}
+
+// NewFilter builds the filter for cfg: the default excludes (unless
+// NoDefaultExcludes), then cfg.Exclude and cfg.Include
+func NewFilter(cfg *config.Config) (*Filter, error) {
+	f := &Filter{exclude: []rule{partialRule}}
+	if !cfg.NoDefaultExcludes {
+		for _, goos := range []string{"darwin", "windows", "linux"} {
+			for _, d := range DefaultExcludes[goos] {
+				f.exclude = append(f.exclude, rule{pattern: d.Pattern, reason: d.Reason})
+			}
+		}
+	}
+	for _, p := range cfg.Exclude {
+		r, err := newRule(p, fmt.Sprintf("matches exclude rule %q", p))
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, r)
+	}
+	for _, p := range cfg.Include {
+		r, err := newRule(p, "")
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, r)
+	}
+	return f, nil
+}
+
+// defaultFilter holds the built-in excludes only
+var defaultFilter, _ = NewFilter(&config.Config{})
+
+// Check returns why relPath (relative to the source) is left out, or "" if
+// it's part of the job
+func (f *Filter) Check(relPath string, isDir bool) string {
+	relPath = strings.ReplaceAll(relPath, "\\", "/")
+	for _, r := range f.exclude {
+		if r.match(relPath) {
+			return r.reason
+		}
+	}
+	if isDir || len(f.include) == 0 {
+		return ""
+	}
+	for _, r := range f.include {
+		if r.match(relPath) {
+			return ""
+		}
+	}
+	return "not matched by any include rule"
+}
+
+// ShouldSkip returns true for system files/dirs (and copy leftovers) that are
+// volatile and should never be copied or verified (they cause hash mismatches).
+// Jobs use Offloader's configured Filter instead.
+func ShouldSkip(name string) bool {
+	return defaultFilter.Check(name, false) != ""
+}
+
+// Filter returns the include/exclude rules of the job, built from Config
+func (o *Offloader) Filter() (*Filter, error) {
+	o.filterOnce.Do(func() { o.filter, o.filterErr = NewFilter(o.Config) })
+	return o.filter, o.filterErr
+}
@@ -0,0 +1,102 @@
+package offload
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"loot/internal/config"
+)
+
+func TestFilterCheck(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Exclude = []string{"*_proxy.mov", "CLIPS001/*.xml", `re:^TMP/`}
+	cfg.Include = []string{"*.mov", "*.R3D", "*.xml"}
+	f, err := NewFilter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		dir  bool
+		kept bool
+	}{
+		{"A001/clip.mov", false, true},
+		{"A001/A001C001.R3D", false, true},
+		{"A001/clip_proxy.mov", false, false},
+		{"CLIPS001/clip.xml", false, false},
+		{"CLIPS002/clip.xml", false, true},
+		{"TMP/clip.mov", false, false},
+		{"A001/notes.txt", false, false}, // not included
+		{"A001", true, true},             // folders are always walked
+		{"A001/._clip.mov", false, false},
+		{"DCIM/Thumbs.db", false, false},
+		{"$RECYCLE.BIN", true, false},
+		{"A001/.clip.mov.loot-partial", false, false},
+	}
+	for _, tt := range tests {
+		if got := f.Check(tt.path, tt.dir); (got == "") != tt.kept {
+			t.Errorf("Check(%q) = %q, kept=%v want kept=%v", tt.path, got, got == "", tt.kept)
+		}
+	}
+
+	if _, err := NewFilter(&config.Config{Exclude: []string{"re:("}}); err == nil {
+		t.Error("invalid regexp should be an error")
+	}
+
+	// Without defaults only the copy leftovers are excluded
+	f, _ = NewFilter(&config.Config{NoDefaultExcludes: true})
+	if f.Check(".DS_Store", false) != "" || f.Check("a"+PartialSuffix, false) == "" {
+		t.Error("NoDefaultExcludes should keep system files but not partial files")
+	}
+}
+
+func TestCopyRecordsExcluded(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_filter_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	dstDir, err := ioutil.TempDir("", "loot_dst_filter_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+
+	for _, name := range []string{"clip.mov", "._clip.mov", "cache/render.tmp"} {
+		path := filepath.Join(srcDir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.MetadataMode = "off"
+	cfg.Exclude = []string{"cache"}
+	o := NewOffloaderWithConfig(cfg, srcDir, dstDir)
+	if err := o.Copy(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := o.Verify(context.Background(), nil); !ok || err != nil {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	if len(o.Files) != 1 || o.Files[0].RelPath != "clip.mov" {
+		t.Errorf("files = %+v, want clip.mov only", o.Files)
+	}
+	if len(o.Excluded) != 2 {
+		t.Fatalf("excluded = %+v, want 2", o.Excluded)
+	}
+	for _, e := range o.Excluded {
+		if e.RelPath == "cache" && (!e.Dir || e.Reason != `matches exclude rule "cache"`) {
+			t.Errorf("cache exclusion = %+v", e)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "cache")); !os.IsNotExist(err) {
+		t.Error("excluded folder should not be created on the destination")
+	}
+}
@@ -0,0 +1,69 @@
+package offload
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// copyLink recreates a source symlink on every active destination. Like
+// files, the link is made under a partial name and renamed into place.
+func (o *Offloader) copyLink(relPath, target string, dests []string) error {
+	info, err := os.Lstat(filepath.Join(o.Source, relPath))
+	if err != nil {
+		return fmt.Errorf("failed to stat link %s: %w", relPath, err)
+	}
+
+	for _, d := range o.activeDestinations() {
+		dst := dests[d]
+		if existing, err := os.Readlink(dst); err == nil && existing == target {
+			continue // Already there (resume / merge)
+		}
+		partial := PartialPath(dst)
+		os.Remove(partial)
+		if err := os.Symlink(target, partial); err != nil {
+			if err := o.destError(o.Destinations[d], relPath, fmt.Errorf("failed to create link %s: %w", dst, err)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Rename(partial, dst); err != nil {
+			os.Remove(partial)
+			if err := o.destError(o.Destinations[d], relPath, fmt.Errorf("failed to rename link %s into place: %w", dst, err)); err != nil {
+				return err
+			}
+			continue
+		}
+	}
+	if len(o.activeDestinations()) == 0 {
+		return ErrAllDestinationsFailed
+	}
+
+	res := FileRes{RelPath: relPath, ModTime: info.ModTime(), Link: target}
+	o.filesMu.Lock()
+	o.Files = append(o.Files, res)
+	o.filesMu.Unlock()
+	if o.OnFile != nil {
+		o.OnFile(res)
+	}
+	return nil
+}
+
+// verifyLink checks that dstPath is a symlink to target
+func verifyLink(dstRoot, dstPath, target string) DestVerify {
+	dv := DestVerify{Destination: dstRoot}
+	got, err := os.Readlink(dstPath)
+	switch {
+	case os.IsNotExist(err):
+		dv.Status = VerifyMissing
+	case err != nil:
+		dv.Status = VerifyMismatch
+		dv.Error = fmt.Sprintf("not a symlink, expected link to %s", target)
+	case got != target:
+		dv.Status = VerifyMismatch
+		dv.Error = fmt.Sprintf("link to %s, expected %s", got, target)
+	default:
+		dv.Status = VerifyOK
+	}
+	return dv
+}
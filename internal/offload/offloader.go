@@ -2,10 +2,14 @@ package offload
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -17,19 +21,14 @@ import (
 
 const BufferSize = 4 * 1024 * 1024 // 4MB
 
-// shouldSkip returns true for macOS system files/dirs that are volatile
-// and should never be copied or verified (they cause hash mismatches).
-func shouldSkip(name string) bool {
-	switch name {
-	case ".DS_Store",
-		".Spotlight-V100",
-		".fseventsd",
-		".Trashes",
-		".TemporaryItems",
-		".DocumentRevisions-V100":
-		return true
-	}
-	return false
+// PartialSuffix marks a copy in progress. Files are written as
+// ".<name>.loot-partial" next to their final name and renamed once synced,
+// so a destination never holds a truncated file under its real name.
+const PartialSuffix = ".loot-partial"
+
+// PartialPath returns the temporary name used while copying to dstPath
+func PartialPath(dstPath string) string {
+	return filepath.Join(filepath.Dir(dstPath), "."+filepath.Base(dstPath)+PartialSuffix)
 }
 
 type ProgressInfo struct {
@@ -39,14 +38,94 @@ type ProgressInfo struct {
 	Speed       float64 // bytes per second
 }
 
+// VerifyStatus is the outcome of checking a single file against its hash
+type VerifyStatus string
+
+const (
+	VerifyOK          VerifyStatus = "ok"
+	VerifyMismatch    VerifyStatus = "mismatch"
+	VerifyMissing     VerifyStatus = "missing"
+	VerifyUnreadable  VerifyStatus = "unreadable"
+	VerifySizeChanged VerifyStatus = "size_changed"
+	VerifyTimeChanged VerifyStatus = "time_changed" // Hash ok, modification time not preserved
+	VerifyExtra       VerifyStatus = "extra"
+)
+
+// DestVerify is the verification outcome of a file on one destination
+type DestVerify struct {
+	Destination string
+	Status      VerifyStatus
+	Error       string `json:",omitempty"`
+}
+
+// ResumeAction is what resume mode decided for a file already on a destination
+type ResumeAction string
+
+const (
+	ResumeSkipped  ResumeAction = "skipped"
+	ResumeRecopied ResumeAction = "recopied"
+)
+
+// DestResume records a resume decision for a file on one destination
+type DestResume struct {
+	Destination string
+	Action      ResumeAction
+	Reason      string
+}
+
 type FileRes struct {
 	RelPath  string
 	Size     int64
 	ModTime  time.Time
 	Hash     hash.HashResult
 	Metadata *metadata.Metadata
+
+	// Per-destination outcome, filled by Verify
+	Verify []DestVerify `json:",omitempty"`
+
+	// Resume decisions for copies that were already on a destination
+	Resume []DestResume `json:",omitempty"`
+
+	// Link is the target of a symlink copied as a link (no content, no hash)
+	Link string `json:",omitempty"`
+	// HardlinkOf is the first file sharing this file's inode on the source.
+	// Both are copied as independent files.
+	HardlinkOf string `json:",omitempty"`
+}
+
+// Verified reports whether the file was verified OK on every destination
+func (f FileRes) Verified() bool {
+	if len(f.Verify) == 0 {
+		return false
+	}
+	for _, v := range f.Verify {
+		if v.Status != VerifyOK {
+			return false
+		}
+	}
+	return true
+}
+
+// StatusFor returns the verification outcome on a destination, if verified
+func (f FileRes) StatusFor(dest string) (VerifyStatus, bool) {
+	for _, v := range f.Verify {
+		if v.Destination == dest {
+			return v.Status, true
+		}
+	}
+	return "", false
 }
 
+// DestFailure records why a destination was dropped (Config.IsolateFailures)
+type DestFailure struct {
+	Destination string
+	File        string // Relative path being written when it failed
+	Error       string
+}
+
+// ErrAllDestinationsFailed is returned by Copy once every destination is degraded
+var ErrAllDestinationsFailed = errors.New("all destinations failed")
+
 // Offloader handles the copy process
 type Offloader struct {
 	Source       string
@@ -60,8 +139,46 @@ type Offloader struct {
 	Files  []FileRes
 	Config *config.Config
 
+	// Hashes recorded by a previous job, per destination root and slash
+	// separated relative path. Resume mode trusts them before re-hashing.
+	PreviousHashes map[string]map[string]hash.HashResult
+
+	// OnFile, if set, is called from the copy workers as soon as a file is
+	// on every destination (or kept by resume)
+	OnFile func(FileRes)
+
+	// Source entries left out by the include/exclude rules, set by Copy
+	Excluded []Exclusion
+
+	filter     *Filter
+	filterErr  error
+	filterOnce sync.Once
+
+	// Bandwidth limits: global, and per destination (nil when uncapped)
+	limiter      *Limiter
+	destLimiters []*Limiter
+	limitOnce    sync.Once
+
+	// Pause holds workers between buffers
+	gate pauseGate
+
 	// Temporary cache for metadata extracted during Copy
 	metadataCache sync.Map
+
+	// Metadata of frame sequences, read once per clip (clip -> *clipMeta)
+	clipMetadata sync.Map
+
+	// Source hashes computed in-flight during Copy (relPath -> hash.HashResult)
+	// so Verify only has to read the destinations back.
+	sourceHashes sync.Map
+	filesMu      sync.Mutex
+
+	// Resume decisions per file (relPath -> []DestResume), kept for Verify
+	resumeNotes sync.Map
+
+	// Destinations dropped after an I/O error (destination root -> failure)
+	degraded   map[string]DestFailure
+	degradedMu sync.Mutex
 }
 
 func NewOffloader(src string, dsts ...string) *Offloader {
@@ -118,6 +235,7 @@ func (t *tracker) update(n int, file string) {
 type copyJob struct {
 	path    string
 	relPath string
+	link    string
 }
 
 func (o *Offloader) Copy(ctx context.Context, progressChan chan<- ProgressInfo) error {
@@ -133,22 +251,14 @@ func (o *Offloader) Copy(ctx context.Context, progressChan chan<- ProgressInfo)
 	}
 
 	if info.IsDir() {
-		// Calculate total size first
-		err := filepath.Walk(o.Source, func(path string, info os.FileInfo, err error) error {
+		// Calculate total size first, and record what the rules leave out
+		o.Excluded = nil
+		err = o.walkSource(ctx, &o.Excluded, func(e entry, err error) error {
 			if err != nil {
 				return err
 			}
-			if err := ctx.Err(); err != nil {
-				return err
-			}
-			if shouldSkip(info.Name()) {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
-			}
-			if !info.IsDir() {
-				t.TotalBytes += info.Size()
+			if !e.info.IsDir() && e.link == "" {
+				t.TotalBytes += e.info.Size()
 			}
 			return nil
 		})
@@ -183,16 +293,25 @@ func (o *Offloader) Copy(ctx context.Context, progressChan chan<- ProgressInfo)
 						for _, dstRoot := range o.Destinations {
 							dstPaths = append(dstPaths, filepath.Join(dstRoot, j.relPath))
 						}
+						if j.link != "" {
+							if err := o.copyLink(j.relPath, j.link, dstPaths); err != nil {
+								select {
+								case results <- err:
+								default:
+								}
+							}
+							continue
+						}
 						// Extract Metadata BEFORE copy (as requested for optimization/streaming)
 						// This primes the OS cache for the header at least.
 						// We ignore error here, we'll try again in verify or just log it?
 						// For now, best effort.
-						meta, _ := metadata.Extract(j.path, o.Config.MetadataMode)
+						meta := o.extractMetadata(j.path, j.relPath)
 						if meta != nil {
 							o.metadataCache.Store(j.relPath, meta)
 						}
 
-						if err := o.copyFileMulti(ctx, j.path, dstPaths, t); err != nil {
+						if err := o.copyFileMulti(ctx, j.path, j.relPath, dstPaths, t); err != nil {
 							select {
 							case results <- err:
 							default:
@@ -206,40 +325,31 @@ func (o *Offloader) Copy(ctx context.Context, progressChan chan<- ProgressInfo)
 		// Feed jobs
 		go func() {
 			defer close(jobs)
-			walkErr := filepath.Walk(o.Source, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					return err
-				}
-				if err := ctx.Err(); err != nil {
-					return err
-				}
-
-				if shouldSkip(info.Name()) {
-					if info.IsDir() {
-						return filepath.SkipDir
-					}
-					return nil
-				}
-
-				relPath, err := filepath.Rel(o.Source, path)
+			walkErr := o.walkSource(ctx, nil, func(e entry, err error) error {
 				if err != nil {
 					return err
 				}
+				relPath, info := e.relPath, e.info
 
 				if info.IsDir() {
 					// Create directories synchronously to ensure they exist for files
-					for _, dstRoot := range o.Destinations {
-						destPath := filepath.Join(dstRoot, relPath)
+					for _, d := range o.activeDestinations() {
+						destPath := filepath.Join(o.Destinations[d], relPath)
 						if err := os.MkdirAll(destPath, info.Mode()); err != nil {
-							return fmt.Errorf("failed to create dir %s: %w", destPath, err)
+							if err := o.destError(o.Destinations[d], relPath, fmt.Errorf("failed to create dir %s: %w", destPath, err)); err != nil {
+								return err
+							}
 						}
 					}
+					if len(o.activeDestinations()) == 0 {
+						return ErrAllDestinationsFailed
+					}
 					return nil
 				}
 
 				// Send file job
 				select {
-				case jobs <- copyJob{path: path, relPath: relPath}:
+				case jobs <- copyJob{path: e.path, relPath: relPath, link: e.link}:
 				case <-ctx.Done(): // Check context during send block
 					return ctx.Err()
 				}
@@ -267,17 +377,68 @@ func (o *Offloader) Copy(ctx context.Context, progressChan chan<- ProgressInfo)
 			}
 		}
 
+		// Workers finish in any order, keep the file list stable for reports
+		sort.Slice(o.Files, func(i, k int) bool { return o.Files[i].RelPath < o.Files[k].RelPath })
+
+		if firstErr == nil {
+			o.sweepPartials()
+		}
 		return firstErr
 
 	} else {
 		// Single file
 		t.TotalBytes = info.Size()
-		return o.copyFileMulti(ctx, o.Source, o.Destinations, t)
+		return o.copyFileMulti(ctx, o.Source, filepath.Base(o.Source), o.Destinations, t)
+	}
+}
+
+// sweepPartials removes the partials left on the destinations by earlier,
+// interrupted runs for files that are no longer copied (excluded or deleted
+// from the source since). Those still on the source were already reused or
+// replaced by this run. Best effort.
+func (o *Offloader) sweepPartials() {
+	for _, d := range o.activeDestinations() {
+		filepath.WalkDir(o.Destinations[d], func(path string, e fs.DirEntry, err error) error {
+			if err == nil && !e.IsDir() && strings.HasSuffix(e.Name(), PartialSuffix) {
+				os.Remove(path)
+			}
+			return nil
+		})
 	}
 }
 
-// copyFileMulti copies src to multiple destinations simultaneously
-func (o *Offloader) copyFileMulti(ctx context.Context, src string, dests []string, t *tracker) error {
+// recordFile stores the copy result of a single file.
+// An empty hash means the file was skipped and never read during Copy.
+func (o *Offloader) recordFile(relPath string, info os.FileInfo, h hash.HashResult, resume []DestResume) {
+	res := FileRes{
+		RelPath: relPath,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		Hash:    h,
+		Resume:  resume,
+	}
+	if len(resume) > 0 {
+		o.resumeNotes.Store(relPath, resume)
+	}
+	if cached, ok := o.metadataCache.Load(relPath); ok {
+		res.Metadata = cached.(*metadata.Metadata)
+	}
+	if h != (hash.HashResult{}) {
+		o.sourceHashes.Store(relPath, h)
+	}
+
+	o.filesMu.Lock()
+	o.Files = append(o.Files, res)
+	o.filesMu.Unlock()
+
+	if o.OnFile != nil {
+		o.OnFile(res)
+	}
+}
+
+// copyFileMulti copies src to multiple destinations simultaneously.
+// dests is indexed like o.Destinations; degraded destinations are skipped.
+func (o *Offloader) copyFileMulti(ctx context.Context, src, relPath string, dests []string, t *tracker) error {
 	// ... (Skipping Stat and Prep logic which doesn't need context explicitly, but loop does)
 
 	// 1. Stat Source first for size comparison
@@ -289,6 +450,7 @@ func (o *Offloader) copyFileMulti(ctx context.Context, src string, dests []strin
 	// 2. Prepare Destinations
 	var openFiles []*os.File
 	var writers []io.Writer
+	var roots, paths, partials []string
 
 	// Cleanup helper
 	defer func() {
@@ -297,36 +459,77 @@ func (o *Offloader) copyFileMulti(ctx context.Context, src string, dests []strin
 			// Normal flow handles Sync and Close explicitly.
 			f.Close()
 		}
+		// Partials still around were never renamed: drop them. Anything
+		// that survives a crash is truncated by the next run.
+		for _, p := range partials {
+			if p != "" {
+				os.Remove(p)
+			}
+		}
 	}()
 
+	active := o.activeDestinations()
+	if len(active) == 0 {
+		return ErrAllDestinationsFailed
+	}
+
+	// Resume: only copies whose hash matches the source are kept
+	var skip map[int]bool
+	var resume []DestResume
+	var srcHash hash.HashResult
+	if o.Config.SkipExisting {
+		skip, resume, srcHash, err = o.resumeCheck(ctx, src, relPath, srcInfo, dests, active)
+		if err != nil {
+			return err
+		}
+	}
+
 	skippedCount := 0
-	for _, dstPath := range dests {
-		// Check for SkipExisting
-		if o.Config.SkipExisting {
-			if dstInfo, err := os.Stat(dstPath); err == nil && !dstInfo.IsDir() {
-				if dstInfo.Size() == srcInfo.Size() {
-					skippedCount++
-					continue
-				}
-			}
+	for _, d := range active {
+		dstPath := dests[d]
+
+		if skip[d] {
+			// A stale partial can sit next to a completed copy
+			os.Remove(PartialPath(dstPath))
+			skippedCount++
+			continue
 		}
 
 		// Ensure parent dir exists
 		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
-			return err
+			if err := o.destError(o.Destinations[d], relPath, err); err != nil {
+				return err
+			}
+			continue
 		}
 
-		f, err := os.Create(dstPath)
+		// Truncates any stale partial left by an interrupted run
+		partial := PartialPath(dstPath)
+		f, err := os.Create(partial)
 		if err != nil {
-			return fmt.Errorf("failed to create dest %s: %w", dstPath, err)
+			if err := o.destError(o.Destinations[d], relPath, fmt.Errorf("failed to create dest %s: %w", partial, err)); err != nil {
+				return err
+			}
+			continue
 		}
 		openFiles = append(openFiles, f)
-		writers = append(writers, f)
+		if l := o.destLimiter(d); l != nil {
+			writers = append(writers, &limitedWriter{w: f, l: l, ctx: ctx})
+		} else {
+			writers = append(writers, f)
+		}
+		roots = append(roots, o.Destinations[d])
+		paths = append(paths, dstPath)
+		partials = append(partials, partial)
 	}
 
 	// If all skipped
 	if len(writers) == 0 {
+		if skippedCount == 0 {
+			return ErrAllDestinationsFailed
+		}
 		t.update(int(srcInfo.Size()), filepath.Base(src)+" (skipped)")
+		o.recordFile(relPath, srcInfo, srcHash, resume)
 		return nil
 	}
 
@@ -343,34 +546,249 @@ func (o *Offloader) copyFileMulti(ctx context.Context, src string, dests []strin
 	defer srcFile.Close()
 
 	// 4. Custom Loop for Copy + Progress + Hash
-	var hashWriter io.Writer
+	var hashWriter *hash.MultiHasher
 	if o.Config.DualHash {
 		hashWriter = hash.NewMultiHasher(config.AlgoXXHash64, config.AlgoMD5)
 	} else {
 		hashWriter = hash.NewHasher(o.Config.Algorithm)
 	}
 
-	err = o.copyFileMultiLoop(ctx, srcFile, writers, hashWriter, t, src)
+	dw := &destWriter{
+		writers: writers,
+		failed:  make([]bool, len(writers)),
+		onFail: func(i int, err error) error {
+			return o.destError(roots[i], relPath, fmt.Errorf("failed to write dest %s: %w", paths[i], err))
+		},
+	}
+
+	err = o.copyFileMultiLoop(ctx, srcFile, []io.Writer{dw}, hashWriter, t, src)
 	if err != nil {
 		return err
 	}
 
-	// 5. Explicitly Sync and Close all destinations to catch physical I/O errors
+	// 5. Explicitly Sync and Close all destinations to catch physical I/O errors,
+	// then move the partials into place
 	for i, f := range openFiles {
+		if dw.failed[i] {
+			// Dropped destination: the deferred cleanup removes its partial
+			f.Close()
+			continue
+		}
 		// Sync flushes buffers to physical disk
 		if syncErr := f.Sync(); syncErr != nil {
-			return fmt.Errorf("failed to sync dest %s: %w", dests[i], syncErr)
+			f.Close()
+			if err := o.destError(roots[i], relPath, fmt.Errorf("failed to sync dest %s: %w", paths[i], syncErr)); err != nil {
+				return err
+			}
+			continue
 		}
 		if closeErr := f.Close(); closeErr != nil {
-			return fmt.Errorf("failed to close dest %s after copy: %w", dests[i], closeErr)
+			if err := o.destError(roots[i], relPath, fmt.Errorf("failed to close dest %s after copy: %w", paths[i], closeErr)); err != nil {
+				return err
+			}
+			continue
+		}
+		if metaErr := o.preserveMetadata(src, partials[i], srcInfo); metaErr != nil {
+			if err := o.destError(roots[i], relPath, metaErr); err != nil {
+				return err
+			}
+			continue
+		}
+		if renameErr := os.Rename(partials[i], paths[i]); renameErr != nil {
+			if err := o.destError(roots[i], relPath, fmt.Errorf("failed to rename %s into place: %w", partials[i], renameErr)); err != nil {
+				return err
+			}
+			continue
 		}
+		partials[i] = ""
+		syncDir(filepath.Dir(paths[i]))
 	}
 	// Clear openFiles so the defer doesn't double-close (double-close is harmless but cleaner this way)
 	openFiles = nil
 
+	if len(o.activeDestinations()) == 0 {
+		return ErrAllDestinationsFailed
+	}
+
+	o.recordFile(relPath, srcInfo, hashWriter.Sum(), resume)
+	return nil
+}
+
+// resumeCheck decides which destinations already hold a good copy of src.
+// A copy is only skipped when its hash matches the source: the hash recorded
+// by a previous job if there is a comparable one, otherwise a fresh re-hash
+// of the destination file.
+func (o *Offloader) resumeCheck(ctx context.Context, src, relPath string, srcInfo os.FileInfo, dests []string, active []int) (map[int]bool, []DestResume, hash.HashResult, error) {
+	skip := make(map[int]bool)
+	var notes []DestResume
+	var srcHash hash.HashResult
+
+	for _, d := range active {
+		dstRoot := o.Destinations[d]
+		dstInfo, err := os.Stat(dests[d])
+		if err != nil || dstInfo.IsDir() {
+			// Not there yet: a plain copy, nothing to note
+			continue
+		}
+
+		note := DestResume{Destination: dstRoot, Action: ResumeRecopied}
+		if dstInfo.Size() != srcInfo.Size() {
+			note.Reason = "size differs"
+			notes = append(notes, note)
+			continue
+		}
+
+		// Hash the source once, and only when something may be skipped
+		if srcHash == (hash.HashResult{}) {
+			if srcHash, err = o.hashWithProgress(ctx, src, nil, &tracker{}); err != nil {
+				return nil, nil, hash.HashResult{}, err
+			}
+		}
+
+		if recorded, ok := o.PreviousHashes[dstRoot][filepath.ToSlash(relPath)]; ok {
+			if match, comparable := srcHash.Compare(recorded); comparable {
+				if match {
+					skip[d] = true
+					note.Action, note.Reason = ResumeSkipped, "hash matches previous MHL"
+				} else {
+					note.Reason = "hash differs from previous MHL"
+				}
+				notes = append(notes, note)
+				continue
+			}
+		}
+
+		dstHash, err := o.hashWithProgress(ctx, dests[d], o.destLimiter(d), &tracker{})
+		switch {
+		case ctx.Err() != nil:
+			return nil, nil, hash.HashResult{}, ctx.Err()
+		case err != nil:
+			note.Reason = fmt.Sprintf("existing copy unreadable: %v", err)
+		case hashesMatch(srcHash, dstHash):
+			skip[d] = true
+			note.Action, note.Reason = ResumeSkipped, "hash matches existing copy"
+		default:
+			note.Reason = "hash differs from existing copy"
+		}
+		notes = append(notes, note)
+	}
+
+	return skip, notes, srcHash, nil
+}
+
+// syncDir flushes a directory entry so a rename survives power loss.
+// Best effort: not every platform or filesystem supports it.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+// destWriter fans writes out to every destination of a file. A destination
+// whose write fails is reported to onFail; if onFail returns nil the
+// destination is dropped and the others keep going.
+//
+// Each chunk goes to all destinations concurrently, so the time spent on a
+// slow disk or under a destination's cap overlaps with the others instead of
+// adding up. A chunk is done once every destination has it: a file copies at
+// the pace of its slowest destination.
+type destWriter struct {
+	writers []io.Writer
+	failed  []bool
+	onFail  func(i int, err error) error
+}
+
+func (w *destWriter) Write(p []byte) (int, error) {
+	errs := make([]error, len(w.writers))
+	var wg sync.WaitGroup
+	for i, dst := range w.writers {
+		if w.failed[i] {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := dst.Write(p)
+			if err == nil && n != len(p) {
+				err = io.ErrShortWrite
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	alive := 0
+	for i, err := range errs {
+		if w.failed[i] {
+			continue
+		}
+		if err != nil {
+			w.failed[i] = true
+			if ferr := w.onFail(i, err); ferr != nil {
+				return 0, ferr
+			}
+			continue
+		}
+		alive++
+	}
+	if alive == 0 {
+		return 0, ErrAllDestinationsFailed
+	}
+	return len(p), nil
+}
+
+// destError handles an I/O error on one destination. Without
+// IsolateFailures it is returned as is; otherwise the destination is marked
+// degraded (first failure wins) and nil is returned.
+func (o *Offloader) destError(dstRoot, relPath string, err error) error {
+	if !o.Config.IsolateFailures {
+		return err
+	}
+
+	o.degradedMu.Lock()
+	defer o.degradedMu.Unlock()
+	if o.degraded == nil {
+		o.degraded = make(map[string]DestFailure)
+	}
+	if _, ok := o.degraded[dstRoot]; !ok {
+		o.degraded[dstRoot] = DestFailure{Destination: dstRoot, File: relPath, Error: err.Error()}
+	}
 	return nil
 }
 
+// IsDegraded reports whether dst was dropped during Copy
+func (o *Offloader) IsDegraded(dst string) bool {
+	o.degradedMu.Lock()
+	defer o.degradedMu.Unlock()
+	_, ok := o.degraded[dst]
+	return ok
+}
+
+// Degraded returns the destinations dropped during Copy, in destination order
+func (o *Offloader) Degraded() []DestFailure {
+	o.degradedMu.Lock()
+	defer o.degradedMu.Unlock()
+	var failures []DestFailure
+	for _, dst := range o.Destinations {
+		if f, ok := o.degraded[dst]; ok {
+			failures = append(failures, f)
+		}
+	}
+	return failures
+}
+
+// activeDestinations returns the indexes of destinations still being written
+func (o *Offloader) activeDestinations() []int {
+	active := make([]int, 0, len(o.Destinations))
+	for i, dst := range o.Destinations {
+		if !o.IsDegraded(dst) {
+			active = append(active, i)
+		}
+	}
+	return active
+}
+
 // BufferPool to reduce GC pressure
 var bufferPool = sync.Pool{
 	New: func() interface{} {
@@ -387,11 +805,14 @@ func (o *Offloader) copyFileMultiLoop(ctx context.Context, srcFile *os.File, wri
 	defer bufferPool.Put(bufPtr)
 	buf := *bufPtr
 
+	o.initLimiters()
 	pw := &progressWriter{
 		w:        multiDest,
 		tracker:  t,
 		fileName: filepath.Base(fileName),
 		ctx:      ctx,
+		limiter:  o.limiter,
+		gate:     &o.gate,
 	}
 
 	_, err := io.CopyBuffer(pw, srcFile, buf)
@@ -403,12 +824,20 @@ type progressWriter struct {
 	tracker  *tracker
 	fileName string
 	ctx      context.Context
+	limiter  *Limiter   // Global bandwidth limit
+	gate     *pauseGate // Job pause
 }
 
 func (pw *progressWriter) Write(p []byte) (n int, err error) {
 	if err := pw.ctx.Err(); err != nil {
 		return 0, err
 	}
+	if err := pw.gate.wait(pw.ctx); err != nil {
+		return 0, err
+	}
+	if err := pw.limiter.Wait(pw.ctx, len(p)); err != nil {
+		return 0, err
+	}
 	n, err = pw.w.Write(p)
 	if n > 0 {
 		pw.tracker.update(n, pw.fileName)
@@ -416,155 +845,306 @@ func (pw *progressWriter) Write(p []byte) (n int, err error) {
 	return
 }
 
-func (o *Offloader) Verify() (bool, error) {
-	// 1. Calculate Source Hash (if not already done/stored)
-	// If we did it during copy, we'd need to map it to file paths.
-	// For now, let's keep the independent verification phase for safety/simplicity
-	// ensuring "Bits on Disk" are read back.
-
-	// We need to verify ALL destinations.
-
-	// Simple approach:
-	// Calculate Source Hash again (read from disk)
-	// Calculate Dest Hash for EACH dest (read from disk)
-	// Comparision.
+func (o *Offloader) Verify(ctx context.Context, progressChan chan<- ProgressInfo) (bool, error) {
+	// Source hashes were captured in-flight during Copy, so only the
+	// destinations are read back from disk here ("Bits on Disk").
+	// Files that were not hashed during Copy fall back to a source read.
+	//
+	// Verification never stops at the first problem: every file is checked
+	// against every destination and the outcome is stored in FileRes.Verify.
 
 	info, err := os.Stat(o.Source)
 	if err != nil {
 		return false, err
 	}
 
+	// Rebuild the file list in walk order from what is actually verified
+	o.Files = nil
+
+	var items []verifyItem
 	if info.IsDir() {
-		return o.verifyDir()
+		items, err = o.collectVerifyItems(ctx)
+		if err != nil {
+			return false, err
+		}
 	} else {
-		return o.verifyFile()
+		items = []verifyItem{{path: o.Source, relPath: filepath.Base(o.Source), info: info, dstPaths: o.Destinations}}
+	}
+
+	if err := o.verifyItems(ctx, items, progressChan); err != nil {
+		return false, err
+	}
+
+	if !info.IsDir() && len(o.Files) == 1 {
+		o.SourceHash = o.Files[0].Hash
+		if o.Files[0].Verified() {
+			o.DestHash = o.Files[0].Hash
+		}
 	}
+
+	return len(o.VerifyFailures()) == 0, nil
 }
 
-func (o *Offloader) verifyDir() (bool, error) {
-	// For Directory verification, we don't have a single "SourceHash" that is easy to check against.
-	// unless we implement a merkle tree or similar.
-	// For now, let's verify file by file.
+// verifyItem is a source file and the destination copies to check it against
+type verifyItem struct {
+	path       string
+	relPath    string
+	info       os.FileInfo
+	dstPaths   []string
+	link       string
+	hardlinkOf string
+}
 
-	// We will set SourceHash/DestHash to a dummy value or the last specific error?
-	// Or maybe "VERIFIED" string?
-	// Since HashResult is structured, we can't just put "VERIFIED".
-	// We'll leave them empty for Dir-level, or use a specific indicator in a future field.
+// collectVerifyItems walks the source. Unreadable entries are recorded
+// directly in o.Files so they show up as failures.
+func (o *Offloader) collectVerifyItems(ctx context.Context) ([]verifyItem, error) {
+	var items []verifyItem
+	err := o.walkSource(ctx, nil, func(e entry, err error) error {
+		relPath, info := e.relPath, e.info
 
-	err := filepath.Walk(o.Source, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return err
-		}
-
-		if shouldSkip(info.Name()) {
-			if info.IsDir() {
+			// Unreadable entry on the source: record it and keep walking
+			o.Files = append(o.Files, o.unreadable(relPath, err))
+			if info != nil && info.IsDir() {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
 		if !info.IsDir() {
-			srcH, err := calculateFileHash(path, o.Config)
-			if err != nil {
-				return err
+			dstPaths := make([]string, len(o.Destinations))
+			for i, dstRoot := range o.Destinations {
+				dstPaths[i] = filepath.Join(dstRoot, relPath)
 			}
+			items = append(items, verifyItem{path: e.path, relPath: relPath, info: info, dstPaths: dstPaths, link: e.link, hardlinkOf: e.hardlinkOf})
+		}
+		return nil
+	})
+	return items, err
+}
+
+// verifyItems checks every (file, destination) pair using Config.Concurrency
+// workers. The source hash of a file is resolved once, by whichever worker
+// gets to it first.
+func (o *Offloader) verifyItems(ctx context.Context, items []verifyItem, progressChan chan<- ProgressInfo) error {
+	t := &tracker{
+		StartTime:    time.Now(),
+		LastUpdate:   time.Now(),
+		ProgressChan: progressChan,
+	}
 
-			relPath, _ := filepath.Rel(o.Source, path)
+	results := make([]FileRes, len(items))
+	srcOnce := make([]sync.Once, len(items))
+	srcErr := make([]error, len(items))
+
+	// Degraded destinations are reported on their own, not per file
+	active := o.activeDestinations()
+
+	for i, it := range items {
+		results[i] = FileRes{
+			RelPath:    it.relPath,
+			Size:       it.info.Size(),
+			ModTime:    it.info.ModTime(),
+			Verify:     make([]DestVerify, len(active)),
+			Link:       it.link,
+			HardlinkOf: it.hardlinkOf,
+		}
+		if it.link != "" {
+			// Links are checked by target, without reading any content
+			results[i].Size = 0
+			continue
+		}
+		t.TotalBytes += it.info.Size() * int64(len(active))
+		if _, ok := o.sourceHashes.Load(it.relPath); !ok {
+			t.TotalBytes += it.info.Size()
+		}
+	}
 
-			// Verify all destinations
-			for _, dstRoot := range o.Destinations {
-				dstPath := filepath.Join(dstRoot, relPath)
-				dstH, err := calculateFileHash(dstPath, o.Config)
-				if err != nil {
-					return fmt.Errorf("failed to hash dest %s: %w", dstPath, err)
-				}
+	// slot indexes FileRes.Verify, dest indexes o.Destinations
+	type task struct{ file, slot, dest int }
+	tasks := make(chan task)
+
+	numWorkers := o.Config.Concurrency
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
 
-				// Compare Primary
-				algo := o.Config.Algorithm
-				if srcH.GetPrimary(algo) != dstH.GetPrimary(algo) {
-					return fmt.Errorf("mismatch: %s vs %s", relPath, dstPath)
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tk := range tasks {
+				it := items[tk.file]
+				res := &results[tk.file]
+
+				if it.link != "" {
+					res.Verify[tk.slot] = verifyLink(o.Destinations[tk.dest], it.dstPaths[tk.dest], it.link)
+					continue
 				}
-				// Check dual hash if enabled
-				if o.Config.DualHash {
-					if srcH.MD5 != dstH.MD5 {
-						return fmt.Errorf("MD5 mismatch: %s vs %s", relPath, dstPath)
+
+				srcOnce[tk.file].Do(func() {
+					if cached, ok := o.sourceHashes.Load(it.relPath); ok {
+						res.Hash = cached.(hash.HashResult)
+						return
 					}
+					res.Hash, srcErr[tk.file] = o.hashWithProgress(ctx, it.path, nil, t)
+				})
+
+				if err := srcErr[tk.file]; err != nil {
+					res.Verify[tk.slot] = DestVerify{Destination: o.Destinations[tk.dest], Status: VerifyUnreadable, Error: fmt.Sprintf("source: %v", err)}
+					continue
 				}
+				res.Verify[tk.slot] = o.verifyDest(ctx, tk.dest, it.dstPaths[tk.dest], res.Hash, it.info, t)
 			}
+		}()
+	}
 
-			// Extract Metadata (best effort)
-			var paramMeta *metadata.Metadata
-			if cached, ok := o.metadataCache.Load(relPath); ok {
-				paramMeta = cached.(*metadata.Metadata)
-			} else {
-				// Fallback if missed during copy
-				paramMeta, _ = metadata.Extract(path, o.Config.MetadataMode)
+feed:
+	for i := range items {
+		for slot, d := range active {
+			select {
+			case tasks <- task{file: i, slot: slot, dest: d}:
+			case <-ctx.Done():
+				break feed
 			}
+		}
+	}
+	close(tasks)
+	wg.Wait()
 
-			// Store metadata (using Source Hash)
-			o.Files = append(o.Files, FileRes{
-				RelPath:  relPath,
-				Size:     info.Size(),
-				ModTime:  info.ModTime(),
-				Hash:     srcH,
-				Metadata: paramMeta,
-			})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for i := range results {
+		if notes, ok := o.resumeNotes.Load(results[i].RelPath); ok {
+			results[i].Resume = notes.([]DestResume)
+		}
+
+		if results[i].Link != "" {
+			continue
 		}
-		return nil
-	})
 
-	// How to represent "DIR VERIFIED" in a hash struct?
-	// Maybe we just don't set SourceHash/DestHash for root dir
-	// OR we set it to XXHash of the list of hashes? Too complex for now.
-	return err == nil, err
+		// Extract Metadata (best effort)
+		if cached, ok := o.metadataCache.Load(results[i].RelPath); ok {
+			results[i].Metadata = cached.(*metadata.Metadata)
+		} else {
+			// Fallback if missed during copy
+			results[i].Metadata = o.extractMetadata(items[i].path, results[i].RelPath)
+		}
+	}
+
+	o.Files = append(o.Files, results...)
+	return nil
 }
 
-func (o *Offloader) verifyFile() (bool, error) {
-	srcH, err := calculateFileHash(o.Source, o.Config)
+// hashWithProgress hashes a file with the configured algorithm(s), reporting progress
+// and honouring cancellation between buffers.
+// dest, if set, is the limiter of the destination being read.
+func (o *Offloader) hashWithProgress(ctx context.Context, path string, dest *Limiter, t *tracker) (hash.HashResult, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return false, err
+		return hash.HashResult{}, err
 	}
-	o.SourceHash = srcH
+	defer f.Close()
 
-	for _, dstPath := range o.Destinations {
-		dstH, err := calculateFileHash(dstPath, o.Config)
-		if err != nil {
-			return false, err
-		}
+	var hasher *hash.MultiHasher
+	if o.Config.DualHash {
+		hasher = hash.NewMultiHasher(config.AlgoXXHash64, config.AlgoMD5)
+	} else {
+		hasher = hash.NewHasher(o.Config.Algorithm)
+	}
 
-		algo := o.Config.Algorithm
-		if srcH.GetPrimary(algo) != dstH.GetPrimary(algo) {
-			return false, fmt.Errorf("mismatch: %s", dstPath)
-		}
-		if o.Config.DualHash {
-			if srcH.MD5 != dstH.MD5 {
-				return false, fmt.Errorf("MD5 mismatch: %s", dstPath)
-			}
-		}
+	bufPtr := bufferPool.Get().(*[]byte)
+	defer bufferPool.Put(bufPtr)
+
+	o.initLimiters()
+	var w io.Writer = hasher
+	if dest != nil {
+		w = &limitedWriter{w: hasher, l: dest, ctx: ctx}
 	}
+	pw := &progressWriter{
+		w:        w,
+		tracker:  t,
+		fileName: filepath.Base(path),
+		ctx:      ctx,
+		limiter:  o.limiter,
+		gate:     &o.gate,
+	}
+	if _, err := io.CopyBuffer(pw, f, *bufPtr); err != nil {
+		return hash.HashResult{}, err
+	}
+	return hasher.Sum(), nil
+}
 
-	o.DestHash = o.SourceHash
+func (o *Offloader) verifyDest(ctx context.Context, d int, dstPath string, srcH hash.HashResult, srcInfo os.FileInfo, t *tracker) DestVerify {
+	dv := DestVerify{Destination: o.Destinations[d]}
 
-	// Metadata
-	info, _ := os.Stat(o.Source)
-	paramMeta, _ := metadata.Extract(o.Source, o.Config.MetadataMode) // Best effort
+	dstInfo, err := os.Stat(dstPath)
+	if os.IsNotExist(err) {
+		dv.Status = VerifyMissing
+		return dv
+	}
 
-	o.Files = append(o.Files, FileRes{
-		RelPath:  filepath.Base(o.Source),
-		Size:     info.Size(),
-		ModTime:  info.ModTime(),
-		Hash:     o.SourceHash,
-		Metadata: paramMeta,
-	})
+	dstH, err := o.hashWithProgress(ctx, dstPath, o.destLimiter(d), t)
+	if err != nil {
+		dv.Status = VerifyUnreadable
+		dv.Error = err.Error()
+		return dv
+	}
+
+	if !hashesMatch(srcH, dstH) {
+		dv.Status = VerifyMismatch
+		dv.Error = fmt.Sprintf("expected %s got %s", srcH, dstH)
+		return dv
+	}
+
+	if o.Config.VerifyTimes && dstInfo != nil && !timesMatch(srcInfo.ModTime(), dstInfo.ModTime()) {
+		dv.Status = VerifyTimeChanged
+		dv.Error = fmt.Sprintf("modified %s, source %s", dstInfo.ModTime().Format(time.RFC3339), srcInfo.ModTime().Format(time.RFC3339))
+		return dv
+	}
+
+	dv.Status = VerifyOK
+	return dv
+}
 
-	return true, nil
+func (o *Offloader) unreadable(relPath string, err error) FileRes {
+	res := FileRes{RelPath: relPath}
+	for _, d := range o.activeDestinations() {
+		dst := o.Destinations[d]
+		res.Verify = append(res.Verify, DestVerify{Destination: dst, Status: VerifyUnreadable, Error: fmt.Sprintf("source: %v", err)})
+	}
+	return res
 }
 
-func calculateFileHash(path string, cfg *config.Config) (hash.HashResult, error) {
-	if cfg.DualHash {
-		return hash.CalculateFileHash(path, config.AlgoXXHash64, config.AlgoMD5)
+// hashesMatch compares every hash present in the source result
+func hashesMatch(src, dst hash.HashResult) bool {
+	if src == (hash.HashResult{}) {
+		return false
+	}
+	if src.XXHash64 != "" && src.XXHash64 != dst.XXHash64 {
+		return false
+	}
+	if src.MD5 != "" && src.MD5 != dst.MD5 {
+		return false
+	}
+	if src.SHA256 != "" && src.SHA256 != dst.SHA256 {
+		return false
+	}
+	return true
+}
+
+// VerifyFailures returns every file that did not verify on all destinations
+func (o *Offloader) VerifyFailures() []FileRes {
+	var failed []FileRes
+	for _, f := range o.Files {
+		if len(f.Verify) > 0 && !f.Verified() {
+			failed = append(failed, f)
+		}
 	}
-	return hash.CalculateFileHash(path, cfg.Algorithm)
+	return failed
 }
 
 // DryRunResult holds the results of a simulation
@@ -573,13 +1153,42 @@ type DryRunResult struct {
 	Files        []FileRes
 	TotalSize    int64
 	Destinations []DestInfo
+
+	Card     *Card            // Camera card at the source, if recognised
+	Issues   []StructureIssue // Structural problems; they block the job unless overridden
+	Excluded []Exclusion      // Left out by the include/exclude rules
 }
 
-// DestInfo holds information about a destination
+// DestInfo holds information about a destination. FreeSpace and CanFit are
+// only meaningful when SpaceKnown.
 type DestInfo struct {
-	Path      string
-	FreeSpace uint64
-	CanFit    bool
+	Path       string
+	FreeSpace  uint64
+	CanFit     bool
+	SpaceKnown bool
+}
+
+// freeSpace returns the space available at path, measured on its nearest
+// existing ancestor since nested destinations are created by the copy
+func freeSpace(path string) (uint64, bool) {
+	path = filepath.Clean(path)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			var stat syscall.Statfs_t
+			if err := syscall.Statfs(path, &stat); err != nil {
+				return 0, false
+			}
+			// Available blocks * block size
+			return uint64(stat.Bavail) * uint64(stat.Bsize), true
+		} else if !os.IsNotExist(err) {
+			return 0, false
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return 0, false
+		}
+		path = parent
+	}
 }
 
 // DryRun simulates the copy operation
@@ -594,26 +1203,26 @@ func (o *Offloader) DryRun() (*DryRunResult, error) {
 	}
 
 	if info.IsDir() {
-		err := filepath.Walk(o.Source, func(path string, info os.FileInfo, err error) error {
+		err := o.walkSource(context.Background(), &result.Excluded, func(e entry, err error) error {
 			if err != nil {
 				return err
 			}
-			if shouldSkip(info.Name()) {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
+			if e.info.IsDir() {
 				return nil
 			}
-			if !info.IsDir() {
-				relPath, _ := filepath.Rel(o.Source, path)
-				result.Files = append(result.Files, FileRes{
-					RelPath:  relPath,
-					Size:     info.Size(),
-					ModTime:  info.ModTime(),
-					Metadata: nil, // No metadata in dry run
-				})
-				result.TotalSize += info.Size()
+			f := FileRes{
+				RelPath:    e.relPath,
+				Size:       e.info.Size(),
+				ModTime:    e.info.ModTime(),
+				Metadata:   nil, // No metadata in dry run
+				Link:       e.link,
+				HardlinkOf: e.hardlinkOf,
 			}
+			if f.Link != "" {
+				f.Size = 0
+			}
+			result.Files = append(result.Files, f)
+			result.TotalSize += f.Size
 			return nil
 		})
 		if err != nil {
@@ -629,28 +1238,20 @@ func (o *Offloader) DryRun() (*DryRunResult, error) {
 		result.TotalSize = info.Size()
 	}
 
+	result.Card = DetectCard(o.Source)
+	result.Issues = CheckStructure(o.Source, result.Card, result.Files)
+
 	// Check destinations
 	for _, dst := range o.Destinations {
 		di := DestInfo{
 			Path: dst,
 		}
 
-		// Get free space (best effort)
-		// We use the parent directory if dst doesn't exist yet
-		checkPath := dst
-		if _, err := os.Stat(checkPath); os.IsNotExist(err) {
-			checkPath = filepath.Dir(checkPath)
-		}
-
-		var stat syscall.Statfs_t
-		if err := syscall.Statfs(checkPath, &stat); err == nil {
-			// Available blocks * block size
-			di.FreeSpace = uint64(stat.Bavail) * uint64(stat.Bsize)
-			di.CanFit = di.FreeSpace > uint64(result.TotalSize)
-		} else {
-			// If we can't check, assume yes but warn?
-			// For now, set FreeSpace to 0 (unknown)
-			di.CanFit = true // Optimistic
+		// Best effort: an unknown free space is reported as such, not as fitting
+		if free, ok := freeSpace(dst); ok {
+			di.FreeSpace = free
+			di.CanFit = free > uint64(result.TotalSize)
+			di.SpaceKnown = true
 		}
 		result.Destinations = append(result.Destinations, di)
 	}
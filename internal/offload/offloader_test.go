@@ -1,13 +1,17 @@
 package offload
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"loot/internal/config"
+	"loot/internal/hash"
 )
 
 func TestOffloader_Copy(t *testing.T) {
@@ -80,8 +84,8 @@ func TestShouldSkip(t *testing.T) {
 		".DocumentRevisions-V100",
 	}
 	for _, name := range skip {
-		if !shouldSkip(name) {
-			t.Errorf("shouldSkip(%q) = false, want true", name)
+		if !ShouldSkip(name) {
+			t.Errorf("ShouldSkip(%q) = false, want true", name)
 		}
 	}
 
@@ -93,8 +97,8 @@ func TestShouldSkip(t *testing.T) {
 		"CLIPINFO.TXT",
 	}
 	for _, name := range keep {
-		if shouldSkip(name) {
-			t.Errorf("shouldSkip(%q) = true, want false", name)
+		if ShouldSkip(name) {
+			t.Errorf("ShouldSkip(%q) = true, want false", name)
 		}
 	}
 }
@@ -153,7 +157,7 @@ func TestCopySkipsSystemFiles(t *testing.T) {
 	}
 
 	// Verify should pass (no mismatch on system files)
-	ok, err := o.Verify()
+	ok, err := o.Verify(context.Background(), progressChan)
 	if err != nil {
 		t.Fatalf("Verify failed: %v", err)
 	}
@@ -161,3 +165,473 @@ func TestCopySkipsSystemFiles(t *testing.T) {
 		t.Error("Verify should return true")
 	}
 }
+
+func TestVerifyUsesHashesFromCopy(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_hashes_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	dstDir, err := ioutil.TempDir("", "loot_dst_hashes_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+
+	if err := ioutil.WriteFile(filepath.Join(srcDir, "clip.mov"), []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Algorithm = "md5"
+	o := NewOffloaderWithConfig(cfg, srcDir, dstDir)
+
+	progressChan := make(chan ProgressInfo, 10)
+	go func() {
+		for range progressChan {
+		}
+	}()
+
+	if err := o.Copy(context.Background(), progressChan); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+
+	// Hash must be recorded per file during Copy
+	if len(o.Files) != 1 || o.Files[0].Hash.MD5 != "421b47ffd946ca083b65cd668c6b17e6" {
+		t.Fatalf("expected in-flight hash for clip.mov, got %+v", o.Files)
+	}
+
+	// Change the source after copy: Verify must compare against the
+	// in-flight hash, not re-read the source
+	if err := ioutil.WriteFile(filepath.Join(srcDir, "clip.mov"), []byte("VIDEO"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := o.Verify(context.Background(), progressChan)
+	if err != nil || !ok {
+		t.Fatalf("Verify should pass from in-flight hashes: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestVerifyCollectsAllFailures(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_failures_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	dstA, err := ioutil.TempDir("", "loot_dst_a_failures_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstA)
+	dstB, err := ioutil.TempDir("", "loot_dst_b_failures_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstB)
+
+	for _, name := range []string{"a.mov", "b.mov", "c.mov"} {
+		if err := ioutil.WriteFile(filepath.Join(srcDir, name), []byte("content "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	o := NewOffloaderWithConfig(cfg, srcDir, dstA, dstB)
+
+	progressChan := make(chan ProgressInfo, 10)
+	go func() {
+		for range progressChan {
+		}
+	}()
+	if err := o.Copy(context.Background(), progressChan); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+
+	// Corrupt one file on A, delete another on B
+	if err := ioutil.WriteFile(filepath.Join(dstA, "a.mov"), []byte("CONTENT a.mov"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dstB, "b.mov")); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := o.Verify(context.Background(), progressChan)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if ok {
+		t.Fatal("Verify should report failure")
+	}
+	if len(o.Files) != 3 {
+		t.Fatalf("all files must be listed, got %d", len(o.Files))
+	}
+
+	want := map[string][2]VerifyStatus{
+		"a.mov": {VerifyMismatch, VerifyOK},
+		"b.mov": {VerifyOK, VerifyMissing},
+		"c.mov": {VerifyOK, VerifyOK},
+	}
+	for _, f := range o.Files {
+		a, _ := f.StatusFor(dstA)
+		b, _ := f.StatusFor(dstB)
+		if [2]VerifyStatus{a, b} != want[f.RelPath] {
+			t.Errorf("%s: got %s/%s, want %v", f.RelPath, a, b, want[f.RelPath])
+		}
+	}
+	if n := len(o.VerifyFailures()); n != 2 {
+		t.Errorf("VerifyFailures() = %d, want 2", n)
+	}
+}
+
+func TestVerifyHonoursCancellation(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_cancel_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	dstDir, err := ioutil.TempDir("", "loot_dst_cancel_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+	if err := ioutil.WriteFile(filepath.Join(srcDir, "clip.mov"), []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	o := NewOffloaderWithConfig(cfg, srcDir, dstDir)
+
+	progressChan := make(chan ProgressInfo, 10)
+	go func() {
+		for range progressChan {
+		}
+	}()
+	if err := o.Copy(context.Background(), progressChan); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ok, err := o.Verify(ctx, progressChan)
+	if ok || err != context.Canceled {
+		t.Fatalf("Verify on cancelled context: ok=%v err=%v, want context.Canceled", ok, err)
+	}
+}
+
+func TestCopyIsolatesFailingDestination(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_isolate_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	for _, name := range []string{"a.bin", "b.bin"} {
+		if err := ioutil.WriteFile(filepath.Join(srcDir, name), []byte("data "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	good, err := ioutil.TempDir("", "loot_dst_good_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(good)
+
+	// A regular file where a directory is expected: every write to it fails
+	bad := filepath.Join(good, "not_a_dir")
+	if err := ioutil.WriteFile(bad, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	goodDst := filepath.Join(good, "copy")
+
+	cfg := config.DefaultConfig()
+	cfg.IsolateFailures = true
+	o := NewOffloaderWithConfig(cfg, srcDir, goodDst, bad)
+
+	progressChan := make(chan ProgressInfo, 100)
+	go func() {
+		for range progressChan {
+		}
+	}()
+	defer close(progressChan)
+
+	if err := o.Copy(context.Background(), progressChan); err != nil {
+		t.Fatalf("Copy failed despite a healthy destination: %v", err)
+	}
+
+	degraded := o.Degraded()
+	if len(degraded) != 1 || degraded[0].Destination != bad {
+		t.Fatalf("Degraded() = %+v, want only %s", degraded, bad)
+	}
+	if degraded[0].File == "" || degraded[0].Error == "" {
+		t.Errorf("failure should name the file and error, got %+v", degraded[0])
+	}
+
+	ok, err := o.Verify(context.Background(), progressChan)
+	if err != nil || !ok {
+		t.Fatalf("Verify on remaining destination = %v, %v", ok, err)
+	}
+	for _, f := range o.Files {
+		if _, verified := f.StatusFor(bad); verified {
+			t.Errorf("%s verified against degraded destination", f.RelPath)
+		}
+		if status, _ := f.StatusFor(goodDst); status != VerifyOK {
+			t.Errorf("%s on healthy destination: %s", f.RelPath, status)
+		}
+	}
+
+	// Without isolation the same failure aborts the copy
+	o = NewOffloaderWithConfig(config.DefaultConfig(), srcDir, filepath.Join(good, "copy2"), bad)
+	if err := o.Copy(context.Background(), progressChan); err == nil {
+		t.Error("Copy should fail when failures are not isolated")
+	}
+}
+
+func TestDestWriterDropsFailingWriter(t *testing.T) {
+	var healthy bytes.Buffer
+	var dropped []int
+	w := &destWriter{
+		writers: []io.Writer{&healthy, failingWriter{}},
+		failed:  make([]bool, 2),
+		onFail: func(i int, err error) error {
+			dropped = append(dropped, i)
+			return nil
+		},
+	}
+
+	for _, chunk := range []string{"one ", "two"} {
+		if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+			t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+		}
+	}
+	if healthy.String() != "one two" {
+		t.Errorf("healthy writer got %q", healthy.String())
+	}
+	if len(dropped) != 1 || dropped[0] != 1 {
+		t.Errorf("dropped = %v, want [1] once", dropped)
+	}
+
+	w.failed[0] = true
+	if _, err := w.Write([]byte("x")); err != ErrAllDestinationsFailed {
+		t.Errorf("Write with no writers left = %v, want ErrAllDestinationsFailed", err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("device not configured")
+}
+
+func TestCopyWritesThroughPartialFile(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_partial_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	src := filepath.Join(srcDir, "clip.mov")
+	if err := ioutil.WriteFile(src, []byte("frames"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dstDir, err := ioutil.TempDir("", "loot_dst_partial_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+	dst := filepath.Join(dstDir, "clip.mov")
+
+	o := NewOffloaderWithConfig(config.DefaultConfig(), src, dstDir)
+	tr := &tracker{ProgressChan: make(chan ProgressInfo, 100)}
+
+	// A cancelled copy leaves neither the final name nor a partial behind
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := o.copyFileMulti(ctx, src, "clip.mov", []string{dst}, tr); err == nil {
+		t.Fatal("copy with cancelled context should fail")
+	}
+	for _, p := range []string{dst, PartialPath(dst)} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist after a cancelled copy", filepath.Base(p))
+		}
+	}
+
+	// A stale partial from a crashed run is replaced by the finished copy
+	if err := ioutil.WriteFile(PartialPath(dst), []byte("fra"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.copyFileMulti(context.Background(), src, "clip.mov", []string{dst}, tr); err != nil {
+		t.Fatalf("copy failed: %v", err)
+	}
+	if data, _ := ioutil.ReadFile(dst); string(data) != "frames" {
+		t.Errorf("destination content = %q, want %q", data, "frames")
+	}
+	if _, err := os.Stat(PartialPath(dst)); !os.IsNotExist(err) {
+		t.Error("partial file left behind after a successful copy")
+	}
+
+	if !ShouldSkip(filepath.Base(PartialPath(dst))) {
+		t.Error("partial files must be skipped by walks")
+	}
+}
+
+func TestResumeSkipsOnlyMatchingHashes(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_resume_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	files := map[string]string{"intact.bin": "good data", "corrupt.bin": "also good", "recorded.bin": "mhl says"}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dstDir, err := ioutil.TempDir("", "loot_dst_resume_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+
+	progressChan := make(chan ProgressInfo, 100)
+	go func() {
+		for range progressChan {
+		}
+	}()
+	defer close(progressChan)
+
+	if err := NewOffloader(srcDir, dstDir).Copy(context.Background(), progressChan); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same size, different content: size-only resume would keep it
+	if err := ioutil.WriteFile(filepath.Join(dstDir, "corrupt.bin"), []byte("also bad!"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.SkipExisting = true
+	o := NewOffloaderWithConfig(cfg, srcDir, dstDir)
+	// A previous MHL that disagrees with the source wins over the re-hash
+	o.PreviousHashes = map[string]map[string]hash.HashResult{
+		dstDir: {"recorded.bin": {XXHash64: "0000000000000000"}},
+	}
+	if err := o.Copy(context.Background(), progressChan); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct {
+		action ResumeAction
+		reason string
+	}{
+		"intact.bin":   {ResumeSkipped, "hash matches existing copy"},
+		"corrupt.bin":  {ResumeRecopied, "hash differs from existing copy"},
+		"recorded.bin": {ResumeRecopied, "hash differs from previous MHL"},
+	}
+	for _, f := range o.Files {
+		w := want[f.RelPath]
+		if len(f.Resume) != 1 || f.Resume[0].Action != w.action || f.Resume[0].Reason != w.reason {
+			t.Errorf("%s: resume = %+v, want %s (%s)", f.RelPath, f.Resume, w.action, w.reason)
+		}
+		if f.Hash == (hash.HashResult{}) {
+			t.Errorf("%s: source hash missing after resume", f.RelPath)
+		}
+	}
+
+	if data, _ := ioutil.ReadFile(filepath.Join(dstDir, "corrupt.bin")); string(data) != "also good" {
+		t.Errorf("corrupt copy not replaced, got %q", data)
+	}
+
+	ok, err := o.Verify(context.Background(), progressChan)
+	if err != nil || !ok {
+		t.Fatalf("Verify after resume = %v, %v", ok, err)
+	}
+	if len(o.Files[0].Resume) == 0 {
+		t.Error("resume decisions lost by Verify")
+	}
+}
+
+func TestDryRunFreeSpaceOfNestedDestination(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_dryrun_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "card")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "clip.mov"), []byte("clip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A file where a folder is expected: no existing ancestor can be measured
+	blocker := filepath.Join(dir, "blocker")
+	if err := ioutil.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	nested := filepath.Join(dir, "raid", "2026-10-17", "A001")
+	res, err := NewOffloader(src, nested, filepath.Join(blocker, "A001")).DryRun()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := res.Destinations[0]; !d.SpaceKnown || !d.CanFit || d.FreeSpace == 0 {
+		t.Errorf("nested destination = %+v, want free space of the nearest existing folder", d)
+	}
+	if d := res.Destinations[1]; d.SpaceKnown || d.CanFit {
+		t.Errorf("unmeasurable destination = %+v, want space unknown", d)
+	}
+}
+
+func TestCopySweepsOrphanedPartials(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_sweep_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	dstDir, err := ioutil.TempDir("", "loot_dst_sweep_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+
+	if err := os.MkdirAll(filepath.Join(srcDir, "A001"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(srcDir, "A001", "clip.mov"), []byte("clip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Left by an interrupted run: a file since deleted from the source, and
+	// one in a folder the source no longer has
+	orphans := []string{
+		PartialPath(filepath.Join(dstDir, "A001", "deleted.mov")),
+		PartialPath(filepath.Join(dstDir, "B002", "excluded.mov")),
+	}
+	for _, p := range orphans {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte("half"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.MetadataMode = "off"
+	o := NewOffloaderWithConfig(cfg, srcDir, dstDir)
+	if err := o.Copy(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range orphans {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("orphaned partial %s still there (%v)", p, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "A001", "clip.mov")); err != nil {
+		t.Errorf("copied file missing: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package offload
+
+import (
+	"context"
+	"sync"
+)
+
+// pauseGate holds workers between buffers while a job is paused. Files stay
+// open, so work picks up at the same offset once the gate opens again.
+type pauseGate struct {
+	mu     sync.Mutex
+	closed chan struct{} // nil while running; closed on resume
+}
+
+func (g *pauseGate) pause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed != nil {
+		return false
+	}
+	g.closed = make(chan struct{})
+	return true
+}
+
+func (g *pauseGate) resume() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed == nil {
+		return false
+	}
+	close(g.closed)
+	g.closed = nil
+	return true
+}
+
+func (g *pauseGate) paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.closed != nil
+}
+
+// wait blocks while the gate is paused. Cancelling ctx ends the wait.
+func (g *pauseGate) wait(ctx context.Context) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	ch := g.closed
+	g.mu.Unlock()
+	if ch == nil {
+		return nil
+	}
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Pause stops copy and verify workers after their current buffer, without
+// closing files. It returns false if already paused.
+func (o *Offloader) Pause() bool {
+	return o.gate.pause()
+}
+
+// Unpause lets paused workers continue where they stopped. It returns false
+// if the offloader wasn't paused.
+func (o *Offloader) Unpause() bool {
+	return o.gate.resume()
+}
+
+// Paused reports whether the offloader is paused
+func (o *Offloader) Paused() bool {
+	return o.gate.paused()
+}
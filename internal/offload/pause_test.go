@@ -0,0 +1,92 @@
+package offload
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"loot/internal/config"
+)
+
+func TestPauseHoldsCopy(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_pause_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	dstDir, err := ioutil.TempDir("", "loot_dst_pause_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+
+	data := bytes.Repeat([]byte("loot"), 256*1024) // 1 MB
+	if err := ioutil.WriteFile(filepath.Join(srcDir, "clip.mov"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.BufferSize = 64 * 1024
+	cfg.MetadataMode = "off"
+	o := NewOffloaderWithConfig(cfg, srcDir, dstDir)
+	if !o.Pause() || o.Pause() {
+		t.Fatal("Pause should succeed once")
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- o.Copy(context.Background(), nil) }()
+
+	// The worker opens the partial, then holds before the first buffer
+	partial := filepath.Join(dstDir, ".clip.mov"+PartialSuffix)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(partial); err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("Copy finished while paused (err %v)", err)
+	default:
+	}
+	if info, err := os.Stat(partial); err != nil || info.Size() != 0 {
+		t.Fatalf("partial while paused: %v, %v", info, err)
+	}
+
+	if !o.Unpause() || o.Paused() {
+		t.Fatal("Unpause should succeed")
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Copy didn't continue after Unpause")
+	}
+	if got, err := ioutil.ReadFile(filepath.Join(dstDir, "clip.mov")); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("copy differs after pause (err %v)", err)
+	}
+}
+
+func TestPauseGateHonoursCancellation(t *testing.T) {
+	var g pauseGate
+	g.pause()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := g.wait(ctx); err != context.Canceled {
+		t.Errorf("wait on cancelled context = %v, want context.Canceled", err)
+	}
+	if !g.resume() || g.resume() {
+		t.Error("resume should succeed once")
+	}
+	if err := g.wait(context.Background()); err != nil {
+		t.Errorf("wait on open gate = %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package offload
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// mtimeWindow is how far a copy's modification time may drift from the
+// source and still verify: FAT stores times with a 2 second resolution
+const mtimeWindow = 2 * time.Second
+
+// preservedMode are the mode bits carried over to copies
+const preservedMode = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+
+// preserveMetadata applies the source's mode bits, extended attributes and
+// access/modification times to dst, as far as Config allows. Filesystems
+// that can't store mode bits, xattrs or times (exFAT, FAT32, SMB) are
+// skipped silently: a copy that lost its times is reported by --verify-times
+// rather than failed here.
+func (o *Offloader) preserveMetadata(src, dst string, info os.FileInfo) error {
+	if !o.Config.NoPreserveMode {
+		if err := os.Chmod(dst, info.Mode()&preservedMode); err != nil && !unsupported(err) {
+			return fmt.Errorf("failed to set mode on %s: %w", dst, err)
+		}
+	}
+	if !o.Config.NoXattrs {
+		if err := copyXattrs(src, dst); err != nil {
+			return fmt.Errorf("failed to copy extended attributes to %s: %w", dst, err)
+		}
+	}
+	// Times last: setting xattrs doesn't touch mtime, but some filesystems
+	// update it on chmod
+	if !o.Config.NoPreserveTimes {
+		if err := os.Chtimes(dst, accessTime(info), info.ModTime()); err != nil && !unsupported(err) {
+			return fmt.Errorf("failed to set times on %s: %w", dst, err)
+		}
+	}
+	return nil
+}
+
+// fileKey identifies an inode: hardlinked files share it
+type fileKey struct {
+	dev, ino uint64
+}
+
+// unsupported reports errors from filesystems without mode bits, xattrs or
+// settable times
+func unsupported(err error) bool {
+	return errors.Is(err, syscall.ENOTSUP) || errors.Is(err, syscall.EOPNOTSUPP) ||
+		errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EACCES)
+}
+
+// timesMatch compares modification times within mtimeWindow
+func timesMatch(a, b time.Time) bool {
+	d := a.Sub(b)
+	if d < 0 {
+		d = -d
+	}
+	return d < mtimeWindow
+}
@@ -0,0 +1,88 @@
+//go:build linux || darwin
+
+package offload
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"loot/internal/config"
+)
+
+func TestCopyPreservesMetadata(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_meta_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	dstDir, err := ioutil.TempDir("", "loot_dst_meta_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+
+	src := filepath.Join(srcDir, "A001C001.mov")
+	if err := ioutil.WriteFile(src, []byte("clip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(src, 0640); err != nil {
+		t.Fatal(err)
+	}
+	shot := time.Date(2024, 3, 7, 9, 30, 0, 0, time.UTC)
+	if err := os.Chtimes(src, shot.Add(time.Hour), shot); err != nil {
+		t.Fatal(err)
+	}
+	// Not every filesystem takes user xattrs; only check them where it does
+	hasXattr := unix.Setxattr(src, "user.loot.reel", []byte("A001"), 0) == nil
+
+	cfg := config.DefaultConfig()
+	cfg.MetadataMode = "off"
+	cfg.VerifyTimes = true
+	o := NewOffloaderWithConfig(cfg, srcDir, dstDir)
+	if err := o.Copy(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dstDir, "A001C001.mov")
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(shot) {
+		t.Errorf("mtime = %s, want %s", info.ModTime(), shot)
+	}
+	if got := accessTime(info); !got.Equal(shot.Add(time.Hour)) {
+		t.Errorf("atime = %s, want %s", got, shot.Add(time.Hour))
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("mode = %v, want 0640", info.Mode().Perm())
+	}
+	if hasXattr {
+		buf := make([]byte, 16)
+		n, err := unix.Getxattr(dst, "user.loot.reel", buf)
+		if err != nil || string(buf[:n]) != "A001" {
+			t.Errorf("xattr = %q, %v; want A001", buf[:n], err)
+		}
+	}
+
+	if ok, err := o.Verify(context.Background(), nil); !ok || err != nil {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	// A copy whose mtime changed afterwards fails the timestamp check
+	if err := os.Chtimes(dst, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := o.Verify(context.Background(), nil); ok {
+		t.Fatal("Verify should fail on a changed modification time")
+	}
+	if got := o.Files[0].Verify[0].Status; got != VerifyTimeChanged {
+		t.Errorf("status = %s, want %s", got, VerifyTimeChanged)
+	}
+}
@@ -0,0 +1,101 @@
+package offload
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"loot/internal/metadata"
+)
+
+// Clip is a frame sequence (ARRIRAW) reported as one logical clip.
+// Its frames are still copied, hashed and verified one by one.
+type Clip struct {
+	Name       string // Relative path without frame number and extension
+	Ext        string
+	FirstFrame int
+	LastFrame  int
+	Frames     int // Frame files found
+	Missing    int `json:",omitempty"` // Gaps between FirstFrame and LastFrame
+	Size       int64
+	Metadata   *metadata.Metadata
+
+	// Frames that failed verification on a destination
+	Failed []FileRes `json:",omitempty"`
+}
+
+// Verified reports whether no frame failed verification
+func (c Clip) Verified() bool {
+	return len(c.Failed) == 0
+}
+
+// Range renders the frame range, e.g. "0-239"
+func (c Clip) Range() string {
+	return fmt.Sprintf("%d-%d", c.FirstFrame, c.LastFrame)
+}
+
+// clipMeta is the metadata shared by the frames of a clip
+type clipMeta struct {
+	once sync.Once
+	meta *metadata.Metadata
+}
+
+// extractMetadata reads a file's metadata. Frames of a sequence share the
+// metadata of the first frame read, so the header is parsed once per clip.
+func (o *Offloader) extractMetadata(path, relPath string) *metadata.Metadata {
+	clip, _, ok := metadata.SequenceFrame(relPath)
+	if !ok {
+		meta, _ := metadata.Extract(path, o.Config.MetadataMode)
+		return meta
+	}
+	v, _ := o.clipMetadata.LoadOrStore(clip, &clipMeta{})
+	c := v.(*clipMeta)
+	c.once.Do(func() {
+		c.meta, _ = metadata.Extract(path, o.Config.MetadataMode)
+	})
+	return c.meta
+}
+
+// GroupClips splits files into frame sequences, one Clip each, and the
+// remaining files. Both keep the order of files.
+func GroupClips(files []FileRes) ([]Clip, []FileRes) {
+	var clips []Clip
+	var rest []FileRes
+	index := make(map[string]int)
+	frames := make(map[string]map[int]bool)
+	for _, f := range files {
+		name, frame, ok := metadata.SequenceFrame(f.RelPath)
+		if !ok || f.Link != "" {
+			rest = append(rest, f)
+			continue
+		}
+		i, seen := index[name]
+		if !seen {
+			i = len(clips)
+			index[name] = i
+			frames[name] = make(map[int]bool)
+			clips = append(clips, Clip{
+				Name: filepath.ToSlash(name), Ext: filepath.Ext(f.RelPath),
+				FirstFrame: frame, LastFrame: frame,
+			})
+		}
+		c := &clips[i]
+		if !frames[name][frame] {
+			frames[name][frame] = true
+			c.Frames++
+		}
+		c.FirstFrame = min(c.FirstFrame, frame)
+		c.LastFrame = max(c.LastFrame, frame)
+		c.Size += f.Size
+		if c.Metadata == nil {
+			c.Metadata = f.Metadata
+		}
+		if len(f.Verify) > 0 && !f.Verified() {
+			c.Failed = append(c.Failed, f)
+		}
+	}
+	for i := range clips {
+		clips[i].Missing = clips[i].LastFrame - clips[i].FirstFrame + 1 - clips[i].Frames
+	}
+	return clips, rest
+}
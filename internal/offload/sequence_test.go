@@ -0,0 +1,110 @@
+package offload
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"loot/internal/config"
+	_ "loot/internal/metadata/parsers" // ARRIRAW header parser
+)
+
+// testARIFrame builds a minimal ARRIRAW frame: header fields read by the parser
+// and a few bytes of image data that differ per frame
+func testARIFrame(n int) []byte {
+	b := make([]byte, 4096+64)
+	copy(b, "ARRI")
+	binary.LittleEndian.PutUint32(b[0x14:], 4448)
+	binary.LittleEndian.PutUint32(b[0x18:], 3096)
+	copy(b[0x29C:], "ALEXA Mini LF")
+	copy(b[4096:], fmt.Sprintf("frame %d", n))
+	return b
+}
+
+func TestCopyGroupsFrameSequences(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	dstDir, err := ioutil.TempDir("", "loot_dst_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+
+	clipDir := filepath.Join(srcDir, "A001C001_230101_R1AB")
+	if err := os.Mkdir(clipDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Frame 3 is missing from the card
+	for _, n := range []int{0, 1, 2, 4} {
+		name := fmt.Sprintf("A001C001_230101_R1AB.%07d.ari", n)
+		if err := ioutil.WriteFile(filepath.Join(clipDir, name), testARIFrame(n), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(srcDir, "notes.txt"), []byte("day 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.MetadataMode = "header"
+	o := NewOffloaderWithConfig(cfg, srcDir, dstDir)
+	progressChan := make(chan ProgressInfo, 10)
+	go func() {
+		for range progressChan {
+		}
+	}()
+	if err := o.Copy(context.Background(), progressChan); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+
+	// Damage one frame on the destination
+	bad := filepath.Join(dstDir, "A001C001_230101_R1AB", "A001C001_230101_R1AB.0000002.ari")
+	if err := ioutil.WriteFile(bad, testARIFrame(99), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := o.Verify(context.Background(), progressChan); err != nil || ok {
+		t.Fatalf("Verify = %v, %v; want a failure", ok, err)
+	}
+
+	if len(o.Files) != 5 {
+		t.Fatalf("%d files recorded, want every frame and the notes", len(o.Files))
+	}
+	for _, f := range o.Files {
+		if f.Hash.String() == "" {
+			t.Errorf("%s was not hashed", f.RelPath)
+		}
+	}
+
+	clips, files := GroupClips(o.Files)
+	if len(files) != 1 || files[0].RelPath != "notes.txt" {
+		t.Errorf("files = %+v", files)
+	}
+	if len(clips) != 1 {
+		t.Fatalf("clips = %+v", clips)
+	}
+	c := clips[0]
+	name := "A001C001_230101_R1AB/A001C001_230101_R1AB"
+	if c.Name != name || c.Ext != ".ari" || c.Range() != "0-4" || c.Frames != 4 || c.Missing != 1 || c.Size != 4*int64(len(testARIFrame(0))) {
+		t.Errorf("clip = %+v", c)
+	}
+	if m := c.Metadata; m == nil || m.Resolution != "4448x3096" || m.ClipName != "A001C001_230101_R1AB" || m.ReelNumber != "A001" {
+		t.Errorf("clip metadata = %+v", c.Metadata)
+	}
+	if len(c.Failed) != 1 || filepath.Base(c.Failed[0].RelPath) != filepath.Base(bad) || c.Verified() {
+		t.Errorf("failed frames = %+v", c.Failed)
+	}
+
+	// The header was read once for the whole clip
+	for _, f := range o.Files {
+		if filepath.Ext(f.RelPath) == ".ari" && f.Metadata != c.Metadata {
+			t.Errorf("%s has its own metadata", f.RelPath)
+		}
+	}
+}
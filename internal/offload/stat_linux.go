@@ -0,0 +1,25 @@
+package offload
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// accessTime returns the last access time from the stat data, or the
+// modification time if it isn't available
+func accessTime(info os.FileInfo) time.Time {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(st.Atim.Unix())
+	}
+	return info.ModTime()
+}
+
+// fileID identifies the inode behind info, with its link count
+func fileID(info os.FileInfo) (key fileKey, nlink uint64, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileKey{}, 0, false
+	}
+	return fileKey{dev: uint64(st.Dev), ino: uint64(st.Ino)}, uint64(st.Nlink), true
+}
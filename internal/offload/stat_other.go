@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package offload
+
+import (
+	"os"
+	"time"
+)
+
+// accessTime falls back to the modification time on this platform
+func accessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
+
+// fileID isn't available on this platform, so hardlinks aren't detected
+func fileID(info os.FileInfo) (key fileKey, nlink uint64, ok bool) {
+	return fileKey{}, 0, false
+}
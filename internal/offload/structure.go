@@ -0,0 +1,204 @@
+package offload
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"loot/internal/metadata"
+)
+
+// StructureIssueKind classifies a problem found on a source card
+type StructureIssueKind string
+
+const (
+	IssueMissingSegment  StructureIssueKind = "missing segment"
+	IssueMissingSidecar  StructureIssueKind = "missing sidecar"
+	IssueOrphanedSidecar StructureIssueKind = "orphaned sidecar"
+	IssueZeroByte        StructureIssueKind = "zero-byte file"
+)
+
+// StructureIssue is one structural problem in the source
+type StructureIssue struct {
+	Kind   StructureIssueKind
+	Path   string // Relative to the source
+	Detail string
+}
+
+func (i StructureIssue) String() string {
+	if i.Detail == "" {
+		return fmt.Sprintf("%s: %s", i.Kind, i.Path)
+	}
+	return fmt.Sprintf("%s: %s (%s)", i.Kind, i.Path, i.Detail)
+}
+
+var (
+	redSegment = regexp.MustCompile(`_(\d{3})\.r3d$`)
+	ariFrame   = regexp.MustCompile(`\.(\d+)\.ari$`)
+)
+
+// CheckStructure validates the source against the layout of its camera card:
+// spanned clips must have every segment, clips their sidecars and sidecars a
+// clip. card may be nil. Zero-byte files are flagged anywhere on a card, and
+// elsewhere only if they are media: an empty .txt in a project folder is fine.
+func CheckStructure(src string, card *Card, files []FileRes) []StructureIssue {
+	var issues []StructureIssue
+	for _, f := range files {
+		if f.Size == 0 && f.Link == "" && (card != nil || metadata.IsMediaExt(filepath.Ext(f.RelPath))) {
+			issues = append(issues, StructureIssue{Kind: IssueZeroByte, Path: f.RelPath})
+		}
+	}
+	if card == nil {
+		return issues
+	}
+
+	rel := func(path string) string {
+		if r, err := filepath.Rel(src, path); err == nil {
+			return r
+		}
+		return path
+	}
+
+	switch card.Vendor {
+	case VendorRED:
+		for _, rdc := range card.ClipDirs {
+			issues = append(issues, checkRDC(rdc, rel)...)
+		}
+	case VendorSony, VendorCanon:
+		for _, dir := range card.ClipDirs {
+			issues = append(issues, checkSidecars(dir, card.ClipExts, card.Sidecars[0], rel)...)
+		}
+	case VendorARRI:
+		for _, dir := range card.ClipDirs {
+			issues = append(issues, checkFrames(dir, rel)...)
+		}
+	}
+	return issues
+}
+
+// checkRDC checks a RED clip folder: R3D segments _001.._NNN without gaps,
+// plus the clip's .RMD
+func checkRDC(rdc string, rel func(string) string) []StructureIssue {
+	clip := strings.TrimSuffix(filepath.Base(rdc), filepath.Ext(rdc))
+	names := lowerNames(rdc)
+
+	var segments []int
+	for name := range names {
+		if m := redSegment.FindStringSubmatch(name); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			segments = append(segments, n)
+		}
+	}
+	sort.Ints(segments)
+
+	var issues []StructureIssue
+	if len(segments) == 0 {
+		issues = append(issues, StructureIssue{Kind: IssueMissingSegment, Path: rel(rdc), Detail: "no R3D segments"})
+	} else {
+		have := make(map[int]bool, len(segments))
+		for _, n := range segments {
+			have[n] = true
+		}
+		last := segments[len(segments)-1]
+		for n := 1; n < last; n++ {
+			if !have[n] {
+				issues = append(issues, StructureIssue{
+					Kind:   IssueMissingSegment,
+					Path:   rel(filepath.Join(rdc, fmt.Sprintf("%s_%03d.R3D", clip, n))),
+					Detail: fmt.Sprintf("segment %d of %d", n, last),
+				})
+			}
+		}
+	}
+
+	_, hasRMD := names[strings.ToLower(clip+".rmd")]
+	switch {
+	case !hasRMD && len(segments) > 0:
+		issues = append(issues, StructureIssue{Kind: IssueMissingSidecar, Path: rel(filepath.Join(rdc, clip+".RMD"))})
+	case hasRMD && len(segments) == 0:
+		issues = append(issues, StructureIssue{Kind: IssueOrphanedSidecar, Path: rel(filepath.Join(rdc, names[strings.ToLower(clip+".rmd")]))})
+	}
+	return issues
+}
+
+// checkSidecars pairs every clip in dir with <clip name><suffix> (Sony
+// "M01.XML", Canon ".XML") and every such sidecar with a clip
+func checkSidecars(dir string, exts []string, suffix string, rel func(string) string) []StructureIssue {
+	names := lowerNames(dir)
+	suffix = strings.ToLower(suffix)
+
+	clips := make(map[string]string) // lower-case base -> name
+	for lower, name := range names {
+		for _, ext := range exts {
+			if strings.HasSuffix(lower, ext) {
+				clips[strings.TrimSuffix(lower, ext)] = name
+			}
+		}
+	}
+
+	var issues []StructureIssue
+	for base, name := range clips {
+		if _, ok := names[base+suffix]; !ok {
+			sidecar := strings.TrimSuffix(name, filepath.Ext(name)) + strings.ToUpper(suffix)
+			issues = append(issues, StructureIssue{Kind: IssueMissingSidecar, Path: rel(filepath.Join(dir, sidecar)), Detail: "for " + name})
+		}
+	}
+	for lower, name := range names {
+		if !strings.HasSuffix(lower, suffix) {
+			continue
+		}
+		if _, ok := clips[strings.TrimSuffix(lower, suffix)]; !ok {
+			issues = append(issues, StructureIssue{Kind: IssueOrphanedSidecar, Path: rel(filepath.Join(dir, name))})
+		}
+	}
+	sortIssues(issues)
+	return issues
+}
+
+// checkFrames reports gaps in an ARRIRAW .ari frame sequence
+func checkFrames(dir string, rel func(string) string) []StructureIssue {
+	var frames []int
+	for lower := range lowerNames(dir) {
+		if m := ariFrame.FindStringSubmatch(lower); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			frames = append(frames, n)
+		}
+	}
+	sort.Ints(frames)
+
+	var issues []StructureIssue
+	for i := 1; i < len(frames); i++ {
+		if gap := frames[i] - frames[i-1]; gap > 1 {
+			detail := fmt.Sprintf("frame %d missing", frames[i-1]+1)
+			if gap > 2 {
+				detail = fmt.Sprintf("frames %d-%d missing", frames[i-1]+1, frames[i]-1)
+			}
+			issues = append(issues, StructureIssue{Kind: IssueMissingSegment, Path: rel(dir), Detail: detail})
+		}
+	}
+	return issues
+}
+
+// lowerNames maps the lower-cased names of the files in dir to their real names
+func lowerNames(dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		names[strings.ToLower(e.Name())] = e.Name()
+	}
+	return names
+}
+
+func sortIssues(issues []StructureIssue) {
+	sort.Slice(issues, func(a, b int) bool { return issues[a].Path < issues[b].Path })
+}
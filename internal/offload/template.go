@@ -0,0 +1,154 @@
+package offload
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"loot/internal/config"
+	"loot/internal/metadata"
+)
+
+// Destination template tokens, e.g. "{project}/{date}/{camera}_{reel}/{card}"
+//
+//	{project}, {job}  Config.JobName
+//	{camera}          Config.Camera, else the CameraID of the first clip
+//	{reel}            Config.Reel, else the ReelNumber of the first clip
+//	{card}            base name of the source
+//	{date}            today, YYYY-MM-DD
+//	{day}             Config.ShootDay (e.g. "D03")
+var templateToken = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// unsafeChars can't appear in a folder name on at least one of the usual
+// shuttle filesystems (exFAT, NTFS, HFS+)
+var unsafeChars = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+// maxProbeFiles bounds how many source files are header-parsed to find
+// camera/reel metadata for the template
+const maxProbeFiles = 20
+
+// ExpandDestTemplate expands cfg.DestTemplate for the source into a relative
+// path. Unknown tokens and tokens without a value are errors, so a job never
+// lands in a half-named folder.
+func ExpandDestTemplate(cfg *config.Config, src string, now time.Time) (string, error) {
+	var meta *metadata.Metadata
+	metaLoaded := false
+	clip := func() *metadata.Metadata {
+		if !metaLoaded {
+			meta = probeMetadata(src)
+			metaLoaded = true
+		}
+		if meta == nil {
+			return &metadata.Metadata{}
+		}
+		return meta
+	}
+
+	var errs []string
+	expanded := templateToken.ReplaceAllStringFunc(cfg.DestTemplate, func(tok string) string {
+		name := tok[1 : len(tok)-1]
+		var value string
+		switch name {
+		case "project", "job":
+			value = cfg.JobName
+		case "camera":
+			value = cfg.Camera
+			if value == "" {
+				value = clip().CameraID
+			}
+		case "reel":
+			value = cfg.Reel
+			if value == "" {
+				value = clip().ReelNumber
+			}
+		case "card":
+			value = filepath.Base(filepath.Clean(src))
+		case "date":
+			value = now.Format("2006-01-02")
+		case "day":
+			value = cfg.ShootDay
+		default:
+			errs = append(errs, fmt.Sprintf("unknown token %s", tok))
+			return tok
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			errs = append(errs, fmt.Sprintf("%s has no value", tok))
+			return tok
+		}
+		return unsafeChars.Replace(value)
+	})
+	if len(errs) > 0 {
+		return "", fmt.Errorf("destination template %q: %s", cfg.DestTemplate, strings.Join(errs, ", "))
+	}
+
+	expanded = filepath.Clean(filepath.FromSlash(expanded))
+	if filepath.IsAbs(expanded) || expanded == ".." || strings.HasPrefix(expanded, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("destination template %q must stay inside the destination", cfg.DestTemplate)
+	}
+	return expanded, nil
+}
+
+// ApplyDestTemplate replaces every destination root in cfg with
+// root/<expanded template>. Without a template cfg is left alone.
+func ApplyDestTemplate(cfg *config.Config, now time.Time) error {
+	if cfg.DestTemplate == "" {
+		return nil
+	}
+	rel, err := ExpandDestTemplate(cfg, cfg.Source, now)
+	if err != nil {
+		return err
+	}
+	for i, root := range cfg.Destinations {
+		cfg.Destinations[i] = filepath.Join(root, rel)
+	}
+	return nil
+}
+
+// probeMetadata returns the first clip metadata carrying a camera or reel id
+func probeMetadata(src string) *metadata.Metadata {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil
+	}
+	if !info.IsDir() {
+		m, _ := metadata.Extract(src, "header")
+		return m
+	}
+
+	var found *metadata.Metadata
+	probed := 0
+	errStop := errors.New("stop probing")
+	filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info == nil {
+			return nil
+		}
+		if ShouldSkip(info.Name()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Only regular files: opening a FIFO or device would block
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		m, _ := metadata.Extract(path, "header")
+		if m == nil {
+			return nil
+		}
+		if m.CameraID != "" || m.ReelNumber != "" {
+			found = m
+			return errStop
+		}
+		if probed++; probed >= maxProbeFiles {
+			return errStop
+		}
+		return nil
+	})
+	return found
+}
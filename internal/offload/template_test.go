@@ -0,0 +1,60 @@
+package offload
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"loot/internal/config"
+)
+
+func TestExpandDestTemplate(t *testing.T) {
+	now := time.Date(2024, 3, 7, 9, 30, 0, 0, time.UTC)
+
+	cfg := config.DefaultConfig()
+	cfg.JobName = "Feature: Act I"
+	cfg.Camera = "A"
+	cfg.Reel = "A001"
+	cfg.ShootDay = "D03"
+	cfg.DestTemplate = "{project}/{date}_{day}/{camera}_{reel}/{card}"
+
+	got, err := ExpandDestTemplate(cfg, "/media/card/A001_C001", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join("Feature_ Act I", "2024-03-07_D03", "A_A001", "A001_C001")
+	if got != want {
+		t.Errorf("ExpandDestTemplate = %q, want %q", got, want)
+	}
+
+	cfg.Destinations = []string{"/raid", "/shuttle"}
+	cfg.Source = "/media/card/A001_C001"
+	if err := ApplyDestTemplate(cfg, now); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Destinations[1] != filepath.Join("/shuttle", want) {
+		t.Errorf("ApplyDestTemplate: %v", cfg.Destinations)
+	}
+}
+
+func TestExpandDestTemplateErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loot_template_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := config.DefaultConfig()
+	for _, tmpl := range []string{
+		"{project}/{card}", // no job name, no metadata fallback
+		"{card}/{shot}",    // unknown token
+		"../{card}",        // escapes the destination
+	} {
+		cfg.DestTemplate = tmpl
+		if _, err := ExpandDestTemplate(cfg, dir, time.Now()); err == nil {
+			t.Errorf("%q: expected an error", tmpl)
+		}
+	}
+}
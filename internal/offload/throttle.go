@@ -0,0 +1,162 @@
+package offload
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MB is the unit of bandwidth limits (MB/s), matching the speeds shown in the UI
+const MB = 1024 * 1024
+
+// Limiter caps a byte rate. It is shared by all copy workers and its limit
+// can be changed while a job runs.
+type Limiter struct {
+	mu      sync.Mutex
+	rate    float64       // Bytes per second, 0 for unlimited
+	next    time.Time     // When the bytes granted so far are paid off
+	changed chan struct{} // Closed when the limit changes, to wake waiters
+}
+
+// NewLimiter returns a limiter for mbps MB/s; 0 means unlimited
+func NewLimiter(mbps float64) *Limiter {
+	l := &Limiter{changed: make(chan struct{})}
+	l.SetLimit(mbps)
+	return l
+}
+
+// SetLimit changes the limit to mbps MB/s (0 for unlimited). Workers
+// waiting under the old limit are released.
+func (l *Limiter) SetLimit(mbps float64) {
+	if mbps < 0 {
+		mbps = 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = mbps * MB
+	l.next = time.Time{}
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// Limit returns the current limit in MB/s, 0 if unlimited
+func (l *Limiter) Limit() float64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate / MB
+}
+
+// Wait blocks until n more bytes fit under the limit. A nil Limiter never waits.
+func (l *Limiter) Wait(ctx context.Context, n int) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	changed := l.changed
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-changed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// limitedWriter throttles writes to one destination
+type limitedWriter struct {
+	w   io.Writer
+	l   *Limiter
+	ctx context.Context
+}
+
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	if err := lw.l.Wait(lw.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return lw.w.Write(p)
+}
+
+// initLimiters builds the global limiter and one per destination that has a
+// cap in Config.DestBWLimits (a cap on a folder applies to everything below it)
+func (o *Offloader) initLimiters() {
+	o.limitOnce.Do(func() {
+		o.limiter = NewLimiter(o.Config.BWLimit)
+		o.destLimiters = make([]*Limiter, len(o.Destinations))
+		for i, dst := range o.Destinations {
+			if mbps, ok := destLimit(o.Config.DestBWLimits, dst); ok {
+				o.destLimiters[i] = NewLimiter(mbps)
+			}
+		}
+	})
+}
+
+// destLimit finds the cap of the closest configured folder containing dst
+func destLimit(limits map[string]float64, dst string) (float64, bool) {
+	best, found := "", false
+	var mbps float64
+	dst = filepath.Clean(dst)
+	for root, limit := range limits {
+		root = filepath.Clean(root)
+		if dst != root && !strings.HasPrefix(dst, root+string(filepath.Separator)) {
+			continue
+		}
+		if !found || len(root) > len(best) {
+			best, mbps, found = root, limit, true
+		}
+	}
+	return mbps, found
+}
+
+// destLimiter returns the limiter of destination d, or nil if it isn't capped
+func (o *Offloader) destLimiter(d int) *Limiter {
+	o.initLimiters()
+	return o.destLimiters[d]
+}
+
+// SetBandwidthLimit changes the global limit (MB/s, 0 for unlimited), also
+// while a job runs
+func (o *Offloader) SetBandwidthLimit(mbps float64) {
+	o.initLimiters()
+	o.limiter.SetLimit(mbps)
+}
+
+// BandwidthLimit returns the global limit in MB/s, 0 if unlimited
+func (o *Offloader) BandwidthLimit() float64 {
+	o.initLimiters()
+	return o.limiter.Limit()
+}
+
+// DestBandwidthLimit returns the cap on destination dst in MB/s, 0 if none
+func (o *Offloader) DestBandwidthLimit(dst string) float64 {
+	o.initLimiters()
+	for i, d := range o.Destinations {
+		if d == dst {
+			return o.destLimiters[i].Limit()
+		}
+	}
+	return 0
+}
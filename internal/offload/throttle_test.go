@@ -0,0 +1,188 @@
+package offload
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"loot/internal/config"
+)
+
+func TestLimiterWait(t *testing.T) {
+	ctx := context.Background()
+
+	// 40 MB/s: the first MB goes through, the next two cost 25ms each
+	l := NewLimiter(40)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx, MB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("3 MB at 40 MB/s took %v, want ~50ms", elapsed)
+	}
+
+	// Unlimited and nil limiters never wait
+	var none *Limiter
+	for _, u := range []*Limiter{NewLimiter(0), none} {
+		start = time.Now()
+		for i := 0; i < 100; i++ {
+			u.Wait(ctx, MB)
+		}
+		if time.Since(start) > 100*time.Millisecond {
+			t.Errorf("unlimited limiter waited %v", time.Since(start))
+		}
+		if u.Limit() != 0 {
+			t.Errorf("Limit() = %g, want 0", u.Limit())
+		}
+	}
+}
+
+func TestLimiterSetLimitReleasesWaiters(t *testing.T) {
+	l := NewLimiter(0.01) // ~10 KB/s: a second MB would take minutes
+	ctx := context.Background()
+	l.Wait(ctx, MB)
+
+	done := make(chan error)
+	go func() { done <- l.Wait(ctx, MB) }()
+	time.Sleep(20 * time.Millisecond)
+	l.SetLimit(0)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiter not released by SetLimit")
+	}
+	if l.Limit() != 0 {
+		t.Errorf("Limit() = %g after SetLimit(0)", l.Limit())
+	}
+
+	// Cancellation ends a wait too
+	l.SetLimit(0.01)
+	l.Wait(ctx, MB)
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.Wait(cctx, MB); err != context.Canceled {
+		t.Errorf("Wait on cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestDestLimit(t *testing.T) {
+	limits := map[string]float64{
+		"/mnt/nas":         80,
+		"/mnt/nas/archive": 20,
+		"/Volumes/SHUTTLE": 200,
+	}
+	tests := []struct {
+		dst  string
+		want float64
+		ok   bool
+	}{
+		{"/mnt/nas", 80, true},
+		{"/mnt/nas/Project/Day01", 80, true},
+		{"/mnt/nas/archive/Day01", 20, true}, // closest folder wins
+		{"/mnt/nas2/Day01", 0, false},
+		{"/Volumes/SHUTTLE/", 200, true},
+		{"/Volumes/RAID", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := destLimit(limits, tt.dst)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("destLimit(%q) = %g, %v; want %g, %v", tt.dst, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCopyWithBandwidthLimits(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "loot_src_bw_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	dstRoot, err := ioutil.TempDir("", "loot_dst_bw_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstRoot)
+
+	data := bytes.Repeat([]byte("loot"), 512*1024) // 2 MB
+	if err := ioutil.WriteFile(filepath.Join(srcDir, "clip.mov"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fast, capped := filepath.Join(dstRoot, "fast"), filepath.Join(dstRoot, "nas", "Day01")
+	cfg := config.DefaultConfig()
+	cfg.BWLimit = 500
+	cfg.DestBWLimits = map[string]float64{filepath.Join(dstRoot, "nas"): 40}
+	o := NewOffloaderWithConfig(cfg, srcDir, fast, capped)
+
+	if got := o.DestBandwidthLimit(capped); got != 40 {
+		t.Errorf("DestBandwidthLimit(capped) = %g, want 40", got)
+	}
+	if got := o.DestBandwidthLimit(fast); got != 0 {
+		t.Errorf("DestBandwidthLimit(fast) = %g, want 0", got)
+	}
+
+	start := time.Now()
+	if err := o.Copy(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	// 2 MB at 40 MB/s on the capped destination: at least ~25ms past the first MB
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("capped copy took %v, expected throttling", elapsed)
+	}
+	for _, dst := range []string{fast, capped} {
+		got, err := ioutil.ReadFile(filepath.Join(dst, "clip.mov"))
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("copy to %s differs (err %v)", dst, err)
+		}
+	}
+
+	o.SetBandwidthLimit(0)
+	if o.BandwidthLimit() != 0 {
+		t.Errorf("BandwidthLimit() = %g after SetBandwidthLimit(0)", o.BandwidthLimit())
+	}
+}
+
+func TestDestWritesOverlap(t *testing.T) {
+	ctx := context.Background()
+
+	// Two disks taking 20ms per MB, one of them also capped at 40 MB/s, and
+	// one fast disk: written side by side 5 MB take ~125ms, not ~200ms
+	w := &destWriter{
+		writers: []io.Writer{
+			slowWriter{20 * time.Millisecond},
+			&limitedWriter{w: slowWriter{20 * time.Millisecond}, l: NewLimiter(40), ctx: ctx},
+			io.Discard,
+		},
+		failed: make([]bool, 3),
+		onFail: func(i int, err error) error { return err },
+	}
+	chunk := make([]byte, MB)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 190*time.Millisecond {
+		t.Errorf("5 MB to two slow destinations took %v, want ~125ms", elapsed)
+	}
+}
+
+// slowWriter takes d for every write
+type slowWriter struct{ d time.Duration }
+
+func (w slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.d)
+	return len(p), nil
+}
@@ -3,26 +3,30 @@ package offload
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 	"syscall"
 )
 
 // Volume represents a mounted storage device
 type Volume struct {
-	Name  string
-	Path  string
-	Total uint64
-	Free  uint64
-	Used  uint64
+	Name     string
+	Path     string
+	Total    uint64
+	Free     uint64
+	Used     uint64
+	FSType   string // e.g. "exfat", "apfs"; empty if unknown
+	Label    string // Filesystem label, if known
+	ReadOnly bool
+	Card     *Card // Camera card layout, if recognised
 }
 
-// GetVolumes returns a list of mounted volumes in /Volumes
+// GetVolumes returns the system volume, the current directory and the
+// removable/external volumes mounted on this platform (/Volumes on macOS;
+// /media, /run/media/$USER and /mnt on Linux)
 func GetVolumes() ([]Volume, error) {
 	var volumes []Volume
 
 	// Add root volume
-	root := Volume{Name: "Macintosh HD", Path: "/"}
+	root := rootVolume()
 	fillDiskUsage(&root)
 	volumes = append(volumes, root)
 
@@ -33,22 +37,13 @@ func GetVolumes() ([]Volume, error) {
 		volumes = append(volumes, cwdVol)
 	}
 
-	entries, err := os.ReadDir("/Volumes")
+	mounted, err := mountedVolumes()
 	if err != nil {
 		return volumes, err // Return what we have
 	}
-
-	for _, entry := range entries {
-		// Skip hidden files/dirs
-		if strings.HasPrefix(entry.Name(), ".") {
-			continue
-		}
-
-		v := Volume{
-			Name: entry.Name(),
-			Path: filepath.Join("/Volumes", entry.Name()),
-		}
+	for _, v := range mounted {
 		fillDiskUsage(&v)
+		v.Card = DetectCard(v.Path)
 		volumes = append(volumes, v)
 	}
 
@@ -0,0 +1,58 @@
+package offload
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// mntReadOnly is MNT_RDONLY from <sys/mount.h>
+const mntReadOnly = 0x1
+
+func rootVolume() Volume {
+	v := Volume{Name: "Macintosh HD", Path: "/"}
+	fillMountInfo(&v)
+	return v
+}
+
+// mountedVolumes lists the volumes in /Volumes
+func mountedVolumes() ([]Volume, error) {
+	entries, err := os.ReadDir("/Volumes")
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []Volume
+	for _, entry := range entries {
+		// Skip hidden files/dirs
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		v := Volume{
+			Name:  entry.Name(),
+			Label: entry.Name(),
+			Path:  filepath.Join("/Volumes", entry.Name()),
+		}
+		fillMountInfo(&v)
+		volumes = append(volumes, v)
+	}
+	return volumes, nil
+}
+
+// fillMountInfo sets the filesystem type and read-only flag from statfs
+func fillMountInfo(v *Volume) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(v.Path, &stat); err != nil {
+		return
+	}
+	var name []byte
+	for _, c := range stat.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+	v.FSType = string(name)
+	v.ReadOnly = stat.Flags&mntReadOnly != 0
+}
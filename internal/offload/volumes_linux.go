@@ -0,0 +1,229 @@
+package offload
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// mountEntry is one line of /proc/self/mountinfo or /proc/mounts
+type mountEntry struct {
+	Source     string
+	MountPoint string
+	FSType     string
+	Options    []string
+}
+
+func (e mountEntry) readOnly() bool {
+	for _, opt := range e.Options {
+		if opt == "ro" {
+			return true
+		}
+	}
+	return false
+}
+
+// pseudoFS are kernel/virtual filesystems that never hold media
+var pseudoFS = map[string]bool{
+	"autofs": true, "binfmt_misc": true, "bpf": true, "cgroup": true, "cgroup2": true,
+	"configfs": true, "debugfs": true, "devpts": true, "devtmpfs": true, "efivarfs": true,
+	"fusectl": true, "hugetlbfs": true, "mqueue": true, "nsfs": true, "overlay": true,
+	"proc": true, "pstore": true, "ramfs": true, "rpc_pipefs": true, "securityfs": true,
+	"selinuxfs": true, "squashfs": true, "sysfs": true, "tmpfs": true, "tracefs": true,
+	"fuse.gvfsd-fuse": true, "fuse.portal": true,
+}
+
+func rootVolume() Volume {
+	v := Volume{Name: "System (/)", Path: "/"}
+	if mounts, err := readMounts(); err == nil {
+		for _, m := range mounts {
+			if m.MountPoint == "/" {
+				v.FSType, v.ReadOnly = m.FSType, m.readOnly()
+			}
+		}
+	}
+	return v
+}
+
+// mountedVolumes lists real filesystems mounted under /media,
+// /run/media/$USER and /mnt
+func mountedVolumes() ([]Volume, error) {
+	mounts, err := readMounts()
+	if err != nil {
+		return nil, err
+	}
+	return removableVolumes(mounts, mountRoots(), diskLabels()), nil
+}
+
+// mountRoots are the folders removable and external drives get mounted in
+func mountRoots() []string {
+	roots := []string{"/media", "/mnt"}
+	if user := os.Getenv("USER"); user != "" {
+		roots = append(roots, filepath.Join("/run/media", user))
+	} else {
+		roots = append(roots, "/run/media")
+	}
+	return roots
+}
+
+// removableVolumes filters mounts down to real filesystems under roots.
+// labels maps device paths to filesystem labels.
+func removableVolumes(mounts []mountEntry, roots []string, labels map[string]string) []Volume {
+	var volumes []Volume
+	seen := make(map[string]int) // mount point -> index; the last mount on a point wins
+	for _, m := range mounts {
+		if pseudoFS[m.FSType] || !underAny(m.MountPoint, roots) {
+			continue
+		}
+		v := Volume{
+			Name:     filepath.Base(m.MountPoint),
+			Path:     m.MountPoint,
+			FSType:   m.FSType,
+			ReadOnly: m.readOnly(),
+			Label:    labels[m.Source],
+		}
+		if v.Label != "" {
+			v.Name = v.Label
+		}
+		if i, ok := seen[m.MountPoint]; ok {
+			volumes[i] = v
+			continue
+		}
+		seen[m.MountPoint] = len(volumes)
+		volumes = append(volumes, v)
+	}
+	return volumes
+}
+
+func underAny(path string, roots []string) bool {
+	for _, root := range roots {
+		if path == root || strings.HasPrefix(path, root+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// readMounts reads /proc/self/mountinfo, falling back to /proc/mounts
+func readMounts() ([]mountEntry, error) {
+	if f, err := os.Open("/proc/self/mountinfo"); err == nil {
+		defer f.Close()
+		return parseMountInfo(f)
+	}
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseMounts(f)
+}
+
+// parseMountInfo parses the mountinfo format:
+//
+//	36 35 98:0 /mnt1 /mnt/parent rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+func parseMountInfo(r io.Reader) ([]mountEntry, error) {
+	var mounts []mountEntry
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		sep := -1
+		for i, f := range fields {
+			if f == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 6 || len(fields) < sep+3 {
+			continue
+		}
+		opts := strings.Split(fields[5], ",")
+		if len(fields) > sep+3 {
+			opts = append(opts, strings.Split(fields[sep+3], ",")...)
+		}
+		mounts = append(mounts, mountEntry{
+			Source:     unescapeMount(fields[sep+2]),
+			MountPoint: unescapeMount(fields[4]),
+			FSType:     fields[sep+1],
+			Options:    opts,
+		})
+	}
+	return mounts, sc.Err()
+}
+
+// parseMounts parses the fstab-like /proc/mounts format
+func parseMounts(r io.Reader) ([]mountEntry, error) {
+	var mounts []mountEntry
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		mounts = append(mounts, mountEntry{
+			Source:     unescapeMount(fields[0]),
+			MountPoint: unescapeMount(fields[1]),
+			FSType:     fields[2],
+			Options:    strings.Split(fields[3], ","),
+		})
+	}
+	return mounts, sc.Err()
+}
+
+// unescapeMount decodes the octal escapes (\040 for space) used in mount tables
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// diskLabels maps device paths (/dev/sdb1) to labels from /dev/disk/by-label
+func diskLabels() map[string]string {
+	const dir = "/dev/disk/by-label"
+	labels := make(map[string]string)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return labels
+	}
+	for _, e := range entries {
+		dev, err := filepath.EvalSymlinks(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		labels[dev] = unescapeLabel(e.Name())
+	}
+	return labels
+}
+
+// unescapeLabel decodes udev's \xNN escapes (\x20 for space)
+func unescapeLabel(s string) string {
+	if !strings.Contains(s, `\x`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], `\x`) && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package offload
+
+import (
+	"strings"
+	"testing"
+)
+
+const testMountInfo = `22 1 8:2 / / rw,relatime shared:1 - ext4 /dev/sda2 rw
+23 22 0:21 / /proc rw,nosuid - proc proc rw
+24 22 0:5 / /dev rw,nosuid shared:2 - devtmpfs udev rw,size=8000k
+45 22 0:40 / /run/user/1000 rw,nosuid - tmpfs tmpfs rw
+61 22 7:3 / /snap/core/123 ro,nodev shared:30 - squashfs /dev/loop3 ro
+70 22 8:17 / /media/dit/A001\040CARD rw,nosuid,nodev shared:40 - exfat /dev/sdb1 rw,uid=1000
+71 22 8:33 / /run/media/dit/SHUTTLE_01 ro,nosuid shared:41 - apfs /dev/sdc1 ro
+72 22 8:49 / /mnt/raid rw,relatime shared:42 - xfs /dev/md0 rw
+73 22 0:50 / /mnt/raid/tmp rw - tmpfs tmpfs rw
+`
+
+func TestRemovableVolumesFromMountInfo(t *testing.T) {
+	mounts, err := parseMountInfo(strings.NewReader(testMountInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mounts) != 9 {
+		t.Fatalf("parsed %d mounts, want 9", len(mounts))
+	}
+
+	labels := map[string]string{"/dev/md0": "RAID 1"}
+	roots := []string{"/media", "/mnt", "/run/media/dit"}
+	vols := removableVolumes(mounts, roots, labels)
+	if len(vols) != 3 {
+		t.Fatalf("volumes = %+v, want 3", vols)
+	}
+
+	card := vols[0]
+	if card.Path != "/media/dit/A001 CARD" || card.Name != "A001 CARD" || card.FSType != "exfat" || card.ReadOnly {
+		t.Errorf("card volume = %+v", card)
+	}
+	if shuttle := vols[1]; shuttle.FSType != "apfs" || !shuttle.ReadOnly {
+		t.Errorf("shuttle volume = %+v, want read-only apfs", shuttle)
+	}
+	if raid := vols[2]; raid.Name != "RAID 1" || raid.Label != "RAID 1" || raid.Path != "/mnt/raid" {
+		t.Errorf("raid volume = %+v, want labelled RAID 1", raid)
+	}
+}
+
+func TestParseMounts(t *testing.T) {
+	mounts, err := parseMounts(strings.NewReader("/dev/sdb1 /media/A\\040B vfat ro,nosuid 0 0\nproc /proc proc rw 0 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mounts) != 2 || mounts[0].MountPoint != "/media/A B" || !mounts[0].readOnly() || mounts[1].readOnly() {
+		t.Errorf("mounts = %+v", mounts)
+	}
+	if got := unescapeLabel(`SHOOT\x20DAY`); got != "SHOOT DAY" {
+		t.Errorf("unescapeLabel = %q", got)
+	}
+}
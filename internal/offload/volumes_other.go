@@ -0,0 +1,12 @@
+//go:build !darwin && !linux
+
+package offload
+
+func rootVolume() Volume {
+	return Volume{Name: "Root (/)", Path: "/"}
+}
+
+// mountedVolumes has no removable volume discovery on this platform
+func mountedVolumes() ([]Volume, error) {
+	return nil, nil
+}
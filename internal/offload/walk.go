@@ -0,0 +1,176 @@
+package offload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"loot/internal/config"
+)
+
+// entry is a source file or folder handed out by walkSource
+type entry struct {
+	path    string      // On the source; inside the link target for followed links
+	relPath string      // Relative to the source, as it lands on the destinations
+	info    os.FileInfo // Lstat, or Stat of the target for followed links
+
+	link       string // Symlink target, for links copied as links
+	hardlinkOf string // First path walked with the same inode, if hardlinked
+}
+
+// symlinkPolicy returns Config.Symlinks, defaulting to copying links as links
+func (o *Offloader) symlinkPolicy() (string, error) {
+	switch p := o.Config.Symlinks; p {
+	case "":
+		return config.SymlinksCopy, nil
+	case config.SymlinksCopy, config.SymlinksFollow, config.SymlinksSkip, config.SymlinksError:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown symlink policy %q (copy, follow, skip or error)", p)
+	}
+}
+
+// specialKind names the file types that are never opened: reading a FIFO or
+// a device can block a worker forever
+func specialKind(mode os.FileMode) string {
+	switch {
+	case mode&os.ModeNamedPipe != 0:
+		return "named pipe"
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	case mode&os.ModeCharDevice != 0:
+		return "character device"
+	case mode&os.ModeDevice != 0:
+		return "device"
+	case mode&os.ModeIrregular != 0:
+		return "irregular file"
+	}
+	return ""
+}
+
+// walkSource walks the source directory in lexical order, applying the
+// include/exclude rules and the symlink policy the same way for every walk.
+// Entries left out (by the rules, as special files or as skipped links) are
+// appended to excluded when that's non-nil. fn follows filepath.WalkFunc
+// conventions: it gets unreadable entries with a non-nil err, and may
+// return filepath.SkipDir for folders.
+func (o *Offloader) walkSource(ctx context.Context, excluded *[]Exclusion, fn func(e entry, err error) error) error {
+	filter, err := o.Filter()
+	if err != nil {
+		return err
+	}
+	policy, err := o.symlinkPolicy()
+	if err != nil {
+		return err
+	}
+
+	exclude := func(e entry, reason string) {
+		if excluded != nil {
+			*excluded = append(*excluded, Exclusion{RelPath: e.relPath, Dir: e.info.IsDir(), Size: e.info.Size(), Reason: reason})
+		}
+	}
+	hardlinks := make(map[fileKey]string)
+	followed := make(map[string]bool) // Real paths of folders entered through links
+	if real, err := filepath.EvalSymlinks(o.Source); err == nil {
+		followed[real] = true
+	}
+
+	var walk func(e entry) error
+	walk = func(e entry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if e.relPath != "." {
+			if reason := filter.Check(e.relPath, e.info.IsDir()); reason != "" {
+				exclude(e, reason)
+				return nil
+			}
+		}
+
+		if e.info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(e.path)
+			if err != nil {
+				return fn(e, err)
+			}
+			switch policy {
+			case config.SymlinksSkip:
+				exclude(e, "symlink to "+target)
+				return nil
+			case config.SymlinksError:
+				return fmt.Errorf("%s is a symlink to %s (symlink policy is %q)", e.relPath, target, policy)
+			case config.SymlinksCopy:
+				e.link = target
+				return fn(e, nil)
+			}
+			// Follow: carry on with what the link points to
+			info, err := os.Stat(e.path)
+			if err != nil {
+				exclude(e, "broken symlink to "+target)
+				return nil
+			}
+			if info.IsDir() {
+				real, err := filepath.EvalSymlinks(e.path)
+				if err != nil || followed[real] {
+					exclude(e, "symlink loop to "+target)
+					return nil
+				}
+				followed[real] = true
+			}
+			e.info = info
+		}
+
+		if kind := specialKind(e.info.Mode()); kind != "" {
+			exclude(e, kind)
+			return nil
+		}
+
+		if !e.info.IsDir() {
+			if key, nlink, ok := fileID(e.info); ok && nlink > 1 {
+				if first, seen := hardlinks[key]; seen {
+					e.hardlinkOf = first
+				} else {
+					hardlinks[key] = e.relPath
+				}
+			}
+			return fn(e, nil)
+		}
+
+		if err := fn(e, nil); err != nil {
+			if err == filepath.SkipDir {
+				return nil
+			}
+			return err
+		}
+		entries, err := os.ReadDir(e.path)
+		if err != nil {
+			if err := fn(e, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			return nil
+		}
+		for _, de := range entries {
+			child := entry{path: filepath.Join(e.path, de.Name()), relPath: filepath.Join(e.relPath, de.Name())}
+			info, err := de.Info()
+			if err != nil {
+				child.info = info
+				if err := fn(child, err); err != nil && err != filepath.SkipDir {
+					return err
+				}
+				continue
+			}
+			child.info = info
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// The source itself is always followed, whatever the policy
+	info, err := os.Stat(o.Source)
+	if err != nil {
+		return fn(entry{path: o.Source, relPath: "."}, err)
+	}
+	return walk(entry{path: o.Source, relPath: ".", info: info})
+}
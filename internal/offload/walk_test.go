@@ -0,0 +1,147 @@
+package offload
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"loot/internal/config"
+	"loot/internal/hash"
+)
+
+// linkSource builds a source with a symlink to a file, a symlink to a
+// folder, a symlink back to the source, a hardlink and a FIFO
+func linkSource(t *testing.T) string {
+	t.Helper()
+	src, err := ioutil.TempDir("", "loot_src_links_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(src) })
+
+	os.MkdirAll(filepath.Join(src, "A001"), 0755)
+	for _, name := range []string{"A001/clip.mov", "notes.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(src, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	steps := []error{
+		os.Symlink("A001/clip.mov", filepath.Join(src, "latest.mov")),
+		os.Symlink("A001", filepath.Join(src, "current")),
+		os.Symlink("..", filepath.Join(src, "A001", "up")),
+		os.Link(filepath.Join(src, "notes.txt"), filepath.Join(src, "notes_copy.txt")),
+		syscall.Mkfifo(filepath.Join(src, "pipe"), 0644),
+	}
+	for _, err := range steps {
+		if err != nil {
+			t.Skipf("can't build link fixture here: %v", err)
+		}
+	}
+	return src
+}
+
+func runCopy(t *testing.T, policy, src string) (*Offloader, string, error) {
+	t.Helper()
+	dst, err := ioutil.TempDir("", "loot_dst_links_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dst) })
+
+	cfg := config.DefaultConfig()
+	cfg.MetadataMode = "off"
+	cfg.Symlinks = policy
+	o := NewOffloaderWithConfig(cfg, src, dst)
+	if err := o.Copy(context.Background(), nil); err != nil {
+		return o, dst, err
+	}
+	if ok, err := o.Verify(context.Background(), nil); !ok || err != nil {
+		t.Fatalf("%s: Verify = %v, %v (%+v)", policy, ok, err, o.VerifyFailures())
+	}
+	return o, dst, nil
+}
+
+func excludedReason(o *Offloader, relPath string) string {
+	for _, e := range o.Excluded {
+		if e.RelPath == relPath {
+			return e.Reason
+		}
+	}
+	return ""
+}
+
+func fileRes(o *Offloader, relPath string) (FileRes, bool) {
+	for _, f := range o.Files {
+		if f.RelPath == relPath {
+			return f, true
+		}
+	}
+	return FileRes{}, false
+}
+
+func TestSymlinkPolicyCopy(t *testing.T) {
+	src := linkSource(t)
+	o, dst, err := runCopy(t, config.SymlinksCopy, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if target, err := os.Readlink(filepath.Join(dst, "latest.mov")); err != nil || target != "A001/clip.mov" {
+		t.Errorf("latest.mov link = %q, %v", target, err)
+	}
+	if f, ok := fileRes(o, "current"); !ok || f.Link != "A001" || !f.Verified() {
+		t.Errorf("current = %+v, want verified link to A001", f)
+	}
+	if f, _ := fileRes(o, "notes_copy.txt"); f.HardlinkOf != "notes.txt" {
+		t.Errorf("notes_copy.txt HardlinkOf = %q, want notes.txt", f.HardlinkOf)
+	}
+	if reason := excludedReason(o, "pipe"); reason != "named pipe" {
+		t.Errorf("pipe excluded as %q, want named pipe", reason)
+	}
+	if _, err := os.Lstat(filepath.Join(dst, "pipe")); !os.IsNotExist(err) {
+		t.Error("FIFO should not be copied")
+	}
+}
+
+func TestSymlinkPolicyFollow(t *testing.T) {
+	src := linkSource(t)
+	o, dst, err := runCopy(t, config.SymlinksFollow, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Lstat(filepath.Join(dst, "current", "clip.mov"))
+	if err != nil || !info.Mode().IsRegular() {
+		t.Fatalf("followed folder not copied as files: %v", err)
+	}
+	if f, ok := fileRes(o, "latest.mov"); !ok || f.Link != "" || f.Hash == (hash.HashResult{}) {
+		t.Errorf("latest.mov = %+v, want hashed content", f)
+	}
+	if reason := excludedReason(o, filepath.Join("A001", "up")); reason != "symlink loop to .." {
+		t.Errorf("A001/up excluded as %q, want a symlink loop", reason)
+	}
+}
+
+func TestSymlinkPolicySkipAndError(t *testing.T) {
+	src := linkSource(t)
+	o, dst, err := runCopy(t, config.SymlinksSkip, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason := excludedReason(o, "latest.mov"); reason != "symlink to A001/clip.mov" {
+		t.Errorf("latest.mov excluded as %q", reason)
+	}
+	if _, err := os.Lstat(filepath.Join(dst, "latest.mov")); !os.IsNotExist(err) {
+		t.Error("skipped link should not be on the destination")
+	}
+
+	if _, _, err := runCopy(t, config.SymlinksError, src); err == nil {
+		t.Error("error policy should refuse a source with links")
+	}
+	if _, _, err := runCopy(t, "sometimes", src); err == nil {
+		t.Error("unknown policy should be an error")
+	}
+}
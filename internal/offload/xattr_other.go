@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package offload
+
+// copyXattrs is a no-op on this platform
+func copyXattrs(src, dst string) error {
+	return nil
+}
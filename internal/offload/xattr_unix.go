@@ -0,0 +1,47 @@
+//go:build linux || darwin
+
+package offload
+
+import (
+	"bytes"
+
+	"golang.org/x/sys/unix"
+)
+
+// copyXattrs copies every extended attribute of src to dst. Attributes the
+// destination refuses (unsupported, or privileged like security.*) are skipped.
+func copyXattrs(src, dst string) error {
+	size, err := unix.Listxattr(src, nil)
+	if err != nil {
+		if unsupported(err) {
+			return nil
+		}
+		return err
+	}
+	if size == 0 {
+		return nil
+	}
+	names := make([]byte, size)
+	if size, err = unix.Listxattr(src, names); err != nil {
+		return err
+	}
+
+	for _, name := range bytes.Split(names[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		attr := string(name)
+		n, err := unix.Getxattr(src, attr, nil)
+		if err != nil {
+			continue // Removed since the listing
+		}
+		value := make([]byte, n)
+		if n, err = unix.Getxattr(src, attr, value); err != nil {
+			continue
+		}
+		if err := unix.Setxattr(dst, attr, value[:n], 0); err != nil && !unsupported(err) {
+			return err
+		}
+	}
+	return nil
+}
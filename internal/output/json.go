@@ -9,19 +9,38 @@ import (
 	"loot/internal/offload"
 )
 
+// DestinationResult is the outcome of a job on a single destination
+type DestinationResult struct {
+	Path        string `json:"path"`
+	Status      string `json:"status"` // "success", "failed", "degraded"
+	FailedFiles int    `json:"failed_files"`
+	FailedAt    string `json:"failed_at,omitempty"` // File being written when the destination was dropped
+	Error       string `json:"error,omitempty"`
+	Report      string `json:"report,omitempty"`
+	MHL         string `json:"mhl,omitempty"`
+
+	// Bandwidth cap on this destination in MB/s (0: none)
+	BWLimitMBps float64 `json:"bwlimit_mbps,omitempty"`
+}
+
 // JobResult represents the final status of an offload job
 type JobResult struct {
-	Timestamp    time.Time         `json:"timestamp"`
-	Source       string            `json:"source"`
-	Destinations []string          `json:"destinations"`
-	Status       string            `json:"status"` // "success", "failed"
-	TotalFiles   int               `json:"total_files"`
-	TotalBytes   int64             `json:"total_bytes"`
-	Duration     string            `json:"duration"`
-	DurationMs   int64             `json:"duration_ms"`
-	SpeedMBps    float64           `json:"speed_mbps"`
-	Files        []offload.FileRes `json:"files,omitempty"`
-	Error        string            `json:"error,omitempty"`
+	Timestamp          time.Time           `json:"timestamp"`
+	Source             string              `json:"source"`
+	Destinations       []string            `json:"destinations"`
+	DestinationResults []DestinationResult `json:"destination_results,omitempty"`
+	Status             string              `json:"status"` // "success", "failed", "degraded"
+	TotalFiles         int                 `json:"total_files"`
+	FailedFiles        int                 `json:"failed_files"`
+	TotalBytes         int64               `json:"total_bytes"`
+	Duration           string              `json:"duration"`
+	DurationMs         int64               `json:"duration_ms"`
+	SpeedMBps          float64             `json:"speed_mbps"`
+	BWLimitMBps        float64             `json:"bwlimit_mbps,omitempty"` // Global limit when the job ended (0: none)
+	Files              []offload.FileRes   `json:"files,omitempty"`        // Frames of sequences are listed as clips
+	Clips              []offload.Clip      `json:"clips,omitempty"`
+	Excluded           []offload.Exclusion `json:"excluded,omitempty"`
+	Error              string              `json:"error,omitempty"`
 }
 
 // PrintJSON outputs the result as formatted JSON to stdout
@@ -36,20 +55,102 @@ func PrintHuman(result JobResult) {
 	fmt.Println()
 	fmt.Printf("Source: %s\n", result.Source)
 	fmt.Println("Destinations:")
-	for _, d := range result.Destinations {
-		fmt.Printf("  - %s\n", d)
+	for _, d := range result.DestinationResults {
+		fmt.Printf("  - %s [%s]\n", d.Path, d.Status)
+		if d.BWLimitMBps > 0 {
+			fmt.Printf("      limited to %g MB/s\n", d.BWLimitMBps)
+		}
+		if d.Status == "degraded" {
+			fmt.Printf("      dropped at %s: %s\n", d.FailedAt, d.Error)
+		}
 	}
 	fmt.Println()
 
-	if result.Status == "success" {
-		fmt.Println("✅ Verification successful!")
+	if result.Status == "success" || result.Status == "degraded" {
+		if result.Status == "degraded" {
+			fmt.Println("⚠️  Completed on the remaining destinations only")
+		} else {
+			fmt.Println("✅ Verification successful!")
+		}
 		fmt.Printf("Processed %d files (%s) in %s\n",
 			result.TotalFiles,
 			offload.FormatBytes(uint64(result.TotalBytes)),
 			result.Duration,
 		)
 		fmt.Printf("Average Speed: %.2f MB/s\n", result.SpeedMBps)
+		if result.BWLimitMBps > 0 {
+			fmt.Printf("Bandwidth Limit: %g MB/s\n", result.BWLimitMBps)
+		}
+		printClips(result.Clips)
+		printResume(result.Files)
+		printExcluded(result.Excluded)
 	} else {
 		fmt.Printf("❌ Job Failed: %s\n", result.Error)
+		files := result.Files
+		for _, c := range result.Clips {
+			files = append(files, c.Failed...)
+		}
+		for _, f := range files {
+			for _, v := range f.Verify {
+				if v.Status != offload.VerifyOK {
+					fmt.Printf("  [%s] %s -> %s\n", v.Status, f.RelPath, v.Destination)
+				}
+			}
+		}
+	}
+}
+
+// printClips lists the frame sequences, one line per clip
+func printClips(clips []offload.Clip) {
+	if len(clips) == 0 {
+		return
+	}
+	fmt.Printf("Clips: %d frame sequence(s)\n", len(clips))
+	for _, c := range clips {
+		fmt.Printf("  [clip] %s%s frames %s (%d frames, %s)", c.Name, c.Ext, c.Range(), c.Frames, offload.FormatBytes(uint64(c.Size)))
+		if c.Missing > 0 {
+			fmt.Printf(", %d missing", c.Missing)
+		}
+		fmt.Println()
+	}
+}
+
+// printResume summarises resume decisions, listing every re-copied file
+func printResume(files []offload.FileRes) {
+	kept, recopied := 0, 0
+	for _, f := range files {
+		for _, r := range f.Resume {
+			if r.Action == offload.ResumeSkipped {
+				kept++
+			} else {
+				recopied++
+			}
+		}
+	}
+	if kept+recopied == 0 {
+		return
+	}
+	fmt.Printf("Resume: %d existing copies kept, %d re-copied\n", kept, recopied)
+	for _, f := range files {
+		for _, r := range f.Resume {
+			if r.Action == offload.ResumeRecopied {
+				fmt.Printf("  [recopied] %s -> %s (%s)\n", f.RelPath, r.Destination, r.Reason)
+			}
+		}
+	}
+}
+
+// printExcluded lists the source entries left out by the include/exclude rules
+func printExcluded(excluded []offload.Exclusion) {
+	if len(excluded) == 0 {
+		return
+	}
+	fmt.Printf("Excluded: %d item(s)\n", len(excluded))
+	for _, e := range excluded {
+		path := e.RelPath
+		if e.Dir {
+			path += "/"
+		}
+		fmt.Printf("  [excluded] %s (%s)\n", path, e.Reason)
 	}
 }
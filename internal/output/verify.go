@@ -0,0 +1,100 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"loot/internal/hash"
+	"loot/internal/offload"
+)
+
+// FileCheck is the outcome of re-checking one file against a hash list
+type FileCheck struct {
+	Path         string               `json:"path"`
+	Status       offload.VerifyStatus `json:"status"`
+	ExpectedSize int64                `json:"expected_size,omitempty"`
+	ActualSize   int64                `json:"actual_size,omitempty"`
+	Expected     hash.HashResult      `json:"expected"`
+	Actual       hash.HashResult      `json:"actual"`
+	Error        string               `json:"error,omitempty"`
+}
+
+// VerifyResult represents the result of `loot verify`
+type VerifyResult struct {
+	Timestamp   time.Time   `json:"timestamp"`
+	Root        string      `json:"root"`
+	Manifests   []string    `json:"manifests"`
+	Status      string      `json:"status"` // "success", "failed"
+	TotalFiles  int         `json:"total_files"`
+	OK          int         `json:"ok"`
+	Missing     int         `json:"missing"`
+	Extra       int         `json:"extra"`
+	SizeChanged int         `json:"size_changed"`
+	Mismatched  int         `json:"mismatched"`
+	Unreadable  int         `json:"unreadable"`
+	Duration    string      `json:"duration"`
+	DurationMs  int64       `json:"duration_ms"`
+	Files       []FileCheck `json:"files"`
+}
+
+// Finish computes counters and the overall status
+func (r *VerifyResult) Finish(duration time.Duration) {
+	r.OK, r.Missing, r.Extra, r.SizeChanged, r.Mismatched, r.Unreadable = 0, 0, 0, 0, 0, 0
+	for _, f := range r.Files {
+		switch f.Status {
+		case offload.VerifyOK:
+			r.OK++
+		case offload.VerifyMissing:
+			r.Missing++
+		case offload.VerifyExtra:
+			r.Extra++
+		case offload.VerifySizeChanged:
+			r.SizeChanged++
+		case offload.VerifyMismatch:
+			r.Mismatched++
+		case offload.VerifyUnreadable:
+			r.Unreadable++
+		}
+	}
+	r.TotalFiles = len(r.Files)
+	r.Duration = duration.String()
+	r.DurationMs = duration.Milliseconds()
+
+	r.Status = "success"
+	if r.OK != r.TotalFiles {
+		r.Status = "failed"
+	}
+}
+
+// PrintVerifyJSON outputs the verify result as formatted JSON to stdout
+func PrintVerifyJSON(result VerifyResult) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// PrintVerifyHuman outputs a human-readable verify summary
+func PrintVerifyHuman(result VerifyResult) {
+	fmt.Println()
+	fmt.Printf("Root: %s\n", result.Root)
+	fmt.Println("Hash lists:")
+	for _, m := range result.Manifests {
+		fmt.Printf("  - %s\n", m)
+	}
+	fmt.Println()
+
+	for _, f := range result.Files {
+		if f.Status != offload.VerifyOK {
+			fmt.Printf("  [%s] %s\n", f.Status, f.Path)
+		}
+	}
+
+	if result.Status == "success" {
+		fmt.Printf("✅ %d files verified in %s\n", result.OK, result.Duration)
+	} else {
+		fmt.Printf("❌ Verification failed: %d ok, %d missing, %d extra, %d size changed, %d mismatched, %d unreadable\n",
+			result.OK, result.Missing, result.Extra, result.SizeChanged, result.Mismatched, result.Unreadable)
+	}
+}
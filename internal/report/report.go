@@ -5,13 +5,14 @@ import (
 	"time"
 
 	"loot/internal/hash"
+	"loot/internal/metadata"
 	"loot/internal/offload"
 
 	"github.com/go-pdf/fpdf"
 )
 
-// GeneratePDF creates a PDF report for the offload operation
-func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time) error {
+// GeneratePDF creates the PDF report of the offload operation for one destination
+func GeneratePDF(path string, o *offload.Offloader, dest string, startTime, endTime time.Time) error {
 	pdf := fpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
 	pdf.SetFont("Arial", "B", 16)
@@ -59,9 +60,33 @@ func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time
 	pdf.Cell(40, 8, fmt.Sprintf("Source:      %s", o.Source))
 	pdf.Ln(6)
 	for i, dst := range o.Destinations {
-		pdf.Cell(40, 8, fmt.Sprintf("Dest %d:      %s", i+1, dst))
+		line := fmt.Sprintf("Dest %d:      %s", i+1, dst)
+		if o.IsDegraded(dst) {
+			line += "  (DEGRADED)"
+		}
+		if dst == dest {
+			line += "  (this report)"
+		}
+		pdf.Cell(40, 8, line)
 		pdf.Ln(6)
 	}
+
+	// Destinations dropped mid-transfer, with the file they failed on
+	degraded := o.Degraded()
+	if len(degraded) > 0 {
+		pdf.SetFont("Arial", "B", 10)
+		pdf.SetTextColor(255, 0, 0) // Red
+		for _, d := range degraded {
+			pdf.Cell(40, 8, fmt.Sprintf("Dropped %s at %s", shorten(d.Destination, 40), shorten(d.File, 40)))
+			pdf.Ln(5)
+			pdf.SetFont("Arial", "", 8)
+			pdf.Cell(40, 8, shorten(d.Error, 110))
+			pdf.Ln(6)
+			pdf.SetFont("Arial", "B", 10)
+		}
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("Arial", "", 12)
+	}
 	pdf.Ln(6)
 	pdf.Cell(40, 8, fmt.Sprintf("Files:       %d", len(o.Files)))
 	pdf.Ln(6)
@@ -91,7 +116,16 @@ func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time
 	pdf.Cell(40, 8, fmt.Sprintf("Duration:   %v", duration.Round(time.Second)))
 	pdf.Ln(6)
 	pdf.Cell(40, 8, fmt.Sprintf("Avg Speed:  %s/s", offload.FormatBytes(uint64(speedBps))))
-	pdf.Ln(8)
+	pdf.Ln(6)
+	if limit := o.BandwidthLimit(); limit > 0 {
+		pdf.Cell(40, 8, fmt.Sprintf("Bandwidth Limit: %g MB/s", limit))
+		pdf.Ln(6)
+	}
+	if limit := o.DestBandwidthLimit(dest); limit > 0 {
+		pdf.Cell(40, 8, fmt.Sprintf("This Destination Limit: %g MB/s", limit))
+		pdf.Ln(6)
+	}
+	pdf.Ln(2)
 
 	// Verification
 	pdf.SetFont("Arial", "B", 14)
@@ -99,7 +133,12 @@ func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time
 	pdf.Ln(8)
 
 	// Check if we have a single root hash (single file) or need to list files
-	if o.SourceHash != (hash.HashResult{}) && o.SourceHash.String() != "" {
+	if o.IsDegraded(dest) {
+		// Whatever is on this destination is incomplete and was not verified
+		pdf.SetFont("Arial", "B", 12)
+		pdf.SetTextColor(255, 0, 0) // Red
+		pdf.Cell(40, 10, "STATUS: DESTINATION DROPPED, COPY INCOMPLETE")
+	} else if o.SourceHash != (hash.HashResult{}) && o.SourceHash.String() != "" {
 		// Single file or root hash available
 		pdf.SetFont("Arial", "", 10)
 		pdf.Cell(40, 10, fmt.Sprintf("Source Hash: %s", o.SourceHash))
@@ -129,13 +168,7 @@ func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time
 		pdf.Ln(8)
 
 		pdf.SetFont("Arial", "", 8)
-		for _, f := range o.Files {
-			// Get relative path shortened
-			relPath := f.RelPath
-			if len(relPath) > 30 {
-				relPath = "..." + relPath[len(relPath)-27:]
-			}
-
+		row := func(name string, m *metadata.Metadata, hashStr string) {
 			// Metadata
 			codec := "-"
 			res := "-"
@@ -143,12 +176,12 @@ func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time
 			tc := "-"
 			dur := "-"
 
-			if f.Metadata != nil {
-				codec = f.Metadata.Codec
-				res = f.Metadata.Resolution
-				fps = f.Metadata.FrameRate
-				tc = f.Metadata.Timecode
-				dur = f.Metadata.Duration
+			if m != nil {
+				codec = m.Codec
+				res = m.Resolution
+				fps = m.FrameRate
+				tc = m.Timecode
+				dur = m.Duration
 
 				// Truncate codec if too long
 				if len(codec) > 10 {
@@ -156,15 +189,7 @@ func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time
 				}
 			}
 
-			hashStr := f.Hash.String()
-			// Shorten hash for display if needed or keep full?
-			// xxHash is 16 chars, MD5 is 32. 35mm width fits ~18 chars at size 8?
-			// Let's take first 16 chars
-			if len(hashStr) > 16 {
-				hashStr = hashStr[:16] + "..."
-			}
-
-			pdf.Cell(55, 6, relPath)
+			pdf.Cell(55, 6, shorten(name, 30))
 			pdf.Cell(20, 6, codec)
 			pdf.Cell(20, 6, res)
 			pdf.Cell(15, 6, fps)
@@ -174,8 +199,63 @@ func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time
 			pdf.Ln(6)
 		}
 
+		// Frame sequences get one line per clip, their frames are hashed one by one
+		clips, files := offload.GroupClips(o.Files)
+		for _, f := range files {
+			hashStr := f.Hash.String()
+			// Shorten hash for display if needed or keep full?
+			// xxHash is 16 chars, MD5 is 32. 35mm width fits ~18 chars at size 8?
+			// Let's take first 16 chars
+			if len(hashStr) > 16 {
+				hashStr = hashStr[:16] + "..."
+			}
+			if f.Link != "" {
+				hashStr = "(symlink)"
+			}
+			row(f.RelPath, f.Metadata, hashStr)
+		}
+		for _, c := range clips {
+			frames := fmt.Sprintf("%d frames", c.Frames)
+			if c.Missing > 0 {
+				frames += fmt.Sprintf(", %d missing", c.Missing)
+			}
+			row(fmt.Sprintf("%s [%s]", c.Name, c.Range()), c.Metadata, frames)
+		}
+
 		pdf.Ln(6)
-		if len(o.Files) > 0 {
+		// Only failures on the destination this report belongs to
+		var failures []offload.FileRes
+		for _, f := range o.Files {
+			if status, ok := f.StatusFor(dest); ok && status != offload.VerifyOK {
+				failures = append(failures, f)
+			}
+		}
+
+		if len(failures) > 0 {
+			pdf.SetFont("Arial", "B", 12)
+			pdf.SetTextColor(255, 0, 0) // Red
+			pdf.Cell(40, 10, fmt.Sprintf("STATUS: %d FILE(S) FAILED VERIFICATION", len(failures)))
+			pdf.Ln(10)
+
+			pdf.SetFont("Arial", "B", 9)
+			pdf.Cell(90, 8, "File")
+			pdf.Cell(25, 8, "Outcome")
+			pdf.Cell(60, 8, "Detail")
+			pdf.Ln(8)
+
+			pdf.SetFont("Arial", "", 8)
+			for _, f := range failures {
+				for _, v := range f.Verify {
+					if v.Destination != dest {
+						continue
+					}
+					pdf.Cell(90, 6, shorten(f.RelPath, 50))
+					pdf.Cell(25, 6, string(v.Status))
+					pdf.Cell(60, 6, shorten(v.Error, 35))
+					pdf.Ln(6)
+				}
+			}
+		} else if len(o.Files) > 0 {
 			pdf.SetFont("Arial", "B", 12)
 			pdf.SetTextColor(0, 128, 0) // Green
 			pdf.Cell(40, 10, "STATUS: ALL FILES VERIFIED")
@@ -183,6 +263,103 @@ func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time
 	}
 	pdf.SetTextColor(0, 0, 0) // Reset
 
+	// Resume decisions for copies that were already on this destination
+	type resumeRow struct {
+		file string
+		r    offload.DestResume
+	}
+	var resumed []resumeRow
+	kept := 0
+	for _, f := range o.Files {
+		for _, r := range f.Resume {
+			if r.Destination != dest {
+				continue
+			}
+			resumed = append(resumed, resumeRow{f.RelPath, r})
+			if r.Action == offload.ResumeSkipped {
+				kept++
+			}
+		}
+	}
+	if len(resumed) > 0 {
+		pdf.Ln(12)
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(40, 10, fmt.Sprintf("Resume (%d kept, %d re-copied)", kept, len(resumed)-kept))
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "B", 9)
+		pdf.Cell(90, 8, "File")
+		pdf.Cell(25, 8, "Decision")
+		pdf.Cell(60, 8, "Reason")
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "", 8)
+		for _, row := range resumed {
+			pdf.Cell(90, 6, shorten(row.file, 50))
+			pdf.Cell(25, 6, string(row.r.Action))
+			pdf.Cell(60, 6, shorten(row.r.Reason, 35))
+			pdf.Ln(6)
+		}
+	}
+
+	// Symlinks copied as links, and files sharing an inode on the source
+	var links []offload.FileRes
+	for _, f := range o.Files {
+		if f.Link != "" || f.HardlinkOf != "" {
+			links = append(links, f)
+		}
+	}
+	if len(links) > 0 {
+		pdf.Ln(12)
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(40, 10, fmt.Sprintf("Links (%d)", len(links)))
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "B", 9)
+		pdf.Cell(90, 8, "File")
+		pdf.Cell(25, 8, "Type")
+		pdf.Cell(60, 8, "Points To")
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "", 8)
+		for _, f := range links {
+			kind, target := "symlink", f.Link
+			if f.Link == "" {
+				kind, target = "hardlink", f.HardlinkOf
+			}
+			pdf.Cell(90, 6, shorten(f.RelPath, 50))
+			pdf.Cell(25, 6, kind)
+			pdf.Cell(60, 6, shorten(target, 35))
+			pdf.Ln(6)
+		}
+	}
+
+	// Source entries the include/exclude rules left out
+	if len(o.Excluded) > 0 {
+		pdf.Ln(12)
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(40, 10, fmt.Sprintf("Excluded (%d)", len(o.Excluded)))
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "B", 9)
+		pdf.Cell(90, 8, "Path")
+		pdf.Cell(25, 8, "Size")
+		pdf.Cell(60, 8, "Reason")
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "", 8)
+		for _, e := range o.Excluded {
+			path, size := e.RelPath, offload.FormatBytes(uint64(e.Size))
+			if e.Dir {
+				path, size = path+"/", "folder"
+			}
+			pdf.Cell(90, 6, shorten(path, 50))
+			pdf.Cell(25, 6, size)
+			pdf.Cell(60, 6, shorten(e.Reason, 35))
+			pdf.Ln(6)
+		}
+	}
+
 	// footer
 	pdf.SetY(-15)
 	pdf.SetFont("Arial", "I", 8)
@@ -190,3 +367,12 @@ func GeneratePDF(path string, o *offload.Offloader, startTime, endTime time.Time
 
 	return pdf.OutputFileAndClose(path)
 }
+
+// shorten keeps the end of long paths, which is the most useful part.
+// It counts runes so accented or non-Latin names are not cut mid-character.
+func shorten(s string, max int) string {
+	if r := []rune(s); len(r) > max {
+		return "..." + string(r[len(r)-(max-3):])
+	}
+	return s
+}
@@ -0,0 +1,28 @@
+package report
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestShorten(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"A001/clip.mov", 20, "A001/clip.mov"},
+		{"Day01/A001/A001C002.mov", 15, "...A001C002.mov"},
+		{"Tournage/Scène_été/Clip_été.mov", 15, "...Clip_été.mov"},
+		{"東京/撮影/カメラ/クリップ.mov", 10, "...リップ.mov"},
+	}
+	for _, tt := range tests {
+		got := shorten(tt.in, tt.max)
+		if got != tt.want {
+			t.Errorf("shorten(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("shorten(%q, %d) returned invalid UTF-8", tt.in, tt.max)
+		}
+	}
+}
@@ -2,9 +2,11 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/progress"
@@ -88,15 +90,22 @@ type jobItem struct {
 
 func (i jobItem) Title() string {
 	statusIcon := "⏳"
-	switch i.j.Status {
+	switch i.j.State() {
 	case job.StatusRunning, job.StatusCopying, job.StatusVerifying:
 		statusIcon = "🚀"
 	case job.StatusCompleted:
 		statusIcon = "✅"
+		if len(i.j.Offloader.Degraded()) > 0 {
+			statusIcon = "⚠️"
+		}
 	case job.StatusFailed:
 		statusIcon = "❌"
 	case job.StatusCancelled:
 		statusIcon = "🚫"
+	case job.StatusPaused:
+		statusIcon = "⏯️"
+	case job.StatusInterrupted:
+		statusIcon = "⏸️"
 	}
 	return fmt.Sprintf("%s %s", statusIcon, filepath.Base(i.j.Offloader.Source))
 }
@@ -106,10 +115,29 @@ func (i jobItem) Description() string {
 	if len(i.j.Offloader.Destinations) == 1 {
 		dest = filepath.Base(i.j.Offloader.Destinations[0])
 	}
-	return fmt.Sprintf("ID: %s | To: %s | Status: %s", i.j.ID, dest, i.j.Status)
+	return fmt.Sprintf("ID: %s | To: %s | Status: %s", i.j.ID, dest, i.j.State())
 }
 func (i jobItem) FilterValue() string { return i.j.ID }
 
+// jobListItems lists the active job first, then pending, completed and failed
+func jobListItems(q *job.Queue) []list.Item {
+	active, pending, completed, failed := q.Snapshot()
+	items := []list.Item{}
+	if active != nil {
+		items = append(items, jobItem{j: active})
+	}
+	for _, j := range pending {
+		items = append(items, jobItem{j: j})
+	}
+	for _, j := range completed {
+		items = append(items, jobItem{j: j})
+	}
+	for _, j := range failed {
+		items = append(items, jobItem{j: j})
+	}
+	return items
+}
+
 func (i fileItem) Title() string       { return i.title }
 func (i fileItem) Description() string { return i.desc }
 func (i fileItem) FilterValue() string { return i.title }
@@ -135,15 +163,17 @@ type Model struct {
 	CurrentJob *job.Job // Keep track of active job for display details
 
 	// Display state
-	status  string
-	err     error
-	spinner spinner.Model
+	status      string
+	verifySpeed float64 // Read speed during verification (bytes/s)
+	err         error
+	spinner     spinner.Model
 
 	width  int
 	height int
 
 	// Dry Run
 	dryRunResult *offload.DryRunResult
+	card         *offload.Card // Camera card detected at the selected source
 
 	config *config.Config
 
@@ -172,11 +202,11 @@ func InitialModelWithConfig(cfg *config.Config) Model {
 
 	// Job List
 	jobList := list.New([]list.Item{}, list.NewDefaultDelegate(), defaultWidth, defaultHeight)
-	jobList.Title = "Job Queue (Tab: Toggle View, X: Cancel, R: Retry/Resume)"
+	jobList.Title = "Job Queue (Tab: Toggle View, X: Cancel, P: Pause/Continue, R: Retry/Resume)"
 	jobList.SetShowHelp(false)
 
 	initialState := stateSelectingSource
-	if cfg.Source != "" && cfg.Destination != "" {
+	if cfg.Source != "" && len(cfg.Destinations) > 0 {
 		initialState = stateCopying
 	}
 
@@ -191,21 +221,27 @@ func InitialModelWithConfig(cfg *config.Config) Model {
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
 	// Initialize dstPaths from config if present
-	dstPaths := []string{}
-	if cfg.Destination != "" {
-		dstPaths = append(dstPaths, cfg.Destination)
-	}
+	dstPaths := append([]string{}, cfg.Destinations...)
 
 	// Initialize Queue
 	q := job.NewQueue()
 	msgChan := make(chan job.Msg, 100)
 
+	// Journal every job so an offload cut short by a crash or reboot is
+	// listed again (and resumable) on the next start
+	status := "Initializing..."
+	if dir, err := job.JournalDir(); err == nil {
+		q.JournalDir = dir
+		if n, _ := q.Restore(); n > 0 {
+			status = fmt.Sprintf("%d interrupted job(s) found: Tab for the Job Manager, R to resume", n)
+		}
+	}
+	jobList.SetItems(jobListItems(q))
+
 	var currentJob *job.Job
 
 	if initialState == stateCopying {
 		currentJob = job.NewJob(cfg)
-		// Force update destinations in offloader (hack until config refactor)
-		currentJob.Offloader.Destinations = dstPaths
 		q.Add(currentJob)
 	}
 
@@ -221,7 +257,7 @@ func InitialModelWithConfig(cfg *config.Config) Model {
 		queue:      q,
 		msgChan:    msgChan,
 		CurrentJob: currentJob, // Initial active job (if likely to start immediately/soon)
-		status:     "Initializing...",
+		status:     status,
 		width:      defaultWidth,
 		height:     defaultHeight,
 		config:     cfg,
@@ -230,14 +266,11 @@ func InitialModelWithConfig(cfg *config.Config) Model {
 
 func (m *Model) Reset(cfg *config.Config) {
 	m.state = stateSelectingSource
-	if cfg.Source != "" && cfg.Destination != "" {
+	if cfg.Source != "" && len(cfg.Destinations) > 0 {
 		m.state = stateCopying
 	}
 	m.srcPath = cfg.Source
-	m.dstPaths = []string{}
-	if cfg.Destination != "" {
-		m.dstPaths = append(m.dstPaths, cfg.Destination)
-	}
+	m.dstPaths = append([]string{}, cfg.Destinations...)
 	m.currentPath = ""
 	m.status = "Ready"
 	m.err = nil
@@ -285,7 +318,18 @@ func loadRootsCmd() tea.Msg {
 	// Quick access to Volumes
 	volumes, _ := offload.GetVolumes()
 	for _, v := range volumes {
-		items = append(items, fileItem{title: "DISK: " + v.Name, desc: v.Path, path: v.Path, isDir: true})
+		desc := v.Path
+		if v.FSType != "" {
+			desc += " (" + v.FSType + ")"
+		}
+		if v.ReadOnly {
+			desc += " [read-only]"
+		}
+		if v.Card != nil {
+			items = append(items, fileItem{title: "CARD: " + v.Name, desc: v.Card.String() + " - " + desc, path: v.Path, isDir: true})
+			continue
+		}
+		items = append(items, fileItem{title: "DISK: " + v.Name, desc: desc, path: v.Path, isDir: true})
 	}
 
 	// Home directory
@@ -386,6 +430,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Pause / continue in Copy/Verify view
+		if (m.state == stateCopying || m.state == stateVerifying) && (msg.String() == "p" || msg.String() == "P") {
+			if m.CurrentJob != nil {
+				m.togglePause(m.CurrentJob)
+			}
+			return m, nil
+		}
+
+		// Live bandwidth limit: +/- in steps, 0 removes it
+		if (m.state == stateCopying || m.state == stateVerifying) && m.CurrentJob != nil {
+			switch msg.String() {
+			case "+", "=", "-", "0":
+				speed := m.CurrentJob.Speed
+				if m.state == stateVerifying {
+					speed = m.verifySpeed
+				}
+				limit := stepLimit(m.CurrentJob.BandwidthLimit(), speed/offload.MB, msg.String())
+				m.CurrentJob.SetBandwidthLimit(limit)
+				return m, nil
+			}
+		}
+
 		// Toggle Job Manager
 		if msg.String() == "tab" {
 			if m.state == stateJobManager {
@@ -412,28 +478,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+			if msg.String() == "p" || msg.String() == "P" {
+				if i, ok := m.jobList.SelectedItem().(jobItem); ok {
+					m.togglePause(i.j)
+					m.jobList.SetItems(jobListItems(m.queue))
+				}
+			}
+
 			if msg.String() == "r" || msg.String() == "R" {
 				if i, ok := m.jobList.SelectedItem().(jobItem); ok {
-					// Allow retrying finished/failed/cancelled jobs
-					if i.j.Status == job.StatusFailed || i.j.Status == job.StatusCancelled || i.j.Status == job.StatusCompleted {
-						// Clone config
-						newCfg := *i.j.Config
-						newCfg.SkipExisting = true // Enable resume mode
-
-						// Create new Job
-						newJob := job.NewJob(&newCfg)
-						// Restore destinations (important for multiple dests)
-						newJob.Offloader.Destinations = i.j.Offloader.Destinations
-
-						m.queue.Add(newJob)
-						m.status = fmt.Sprintf("Retrying job %s...", i.j.ID)
+					// Allow retrying finished/failed/cancelled jobs and resuming interrupted ones
+					switch i.j.State() {
+					case job.StatusFailed, job.StatusCancelled, job.StatusCompleted, job.StatusInterrupted:
+						// The new run keeps the job ID and resumes by hash
+						if _, ok := m.queue.Resume(i.j.ID); ok {
+							m.status = fmt.Sprintf("Resuming job %s...", i.j.ID)
+						}
 						m.err = nil // Clear error state
 					}
 				}
 			}
 			if msg.String() == "enter" {
 				if i, ok := m.jobList.SelectedItem().(jobItem); ok {
-					if i.j.Status == job.StatusFailed && i.j.Err != nil {
+					if i.j.State() == job.StatusFailed && i.j.Err != nil {
 						m.CurrentJob = i.j
 						m.state = stateErrorDetails
 						return m, nil
@@ -454,12 +521,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Dry Run Interaction
 		if m.state == stateDryRun {
-			if msg.String() == "c" || msg.String() == "C" {
-				// Continue to Actual Copy
+			incomplete := m.dryRunResult != nil && len(m.dryRunResult.Issues) > 0
+			if (msg.String() == "c" || msg.String() == "C") && (!incomplete || m.config.IgnoreStructure) ||
+				(msg.String() == "o" || msg.String() == "O") && incomplete {
+				// Continue to Actual Copy; 'o' overrides the card structure check
 				m.state = stateCopying
-				j := job.NewJob(m.config)
-				j.Offloader.Destinations = m.dstPaths
-				m.queue.Add(j)
+				jobCfg := *m.config
+				jobCfg.IgnoreStructure = jobCfg.IgnoreStructure || incomplete
+				m.queue.Add(job.NewJob(&jobCfg))
 				return m, nil
 			}
 			if msg.String() == "q" || msg.String() == "Q" || msg.String() == "esc" {
@@ -506,6 +575,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if i, ok := activeList.SelectedItem().(fileItem); ok {
 					if m.state == stateSelectingSource {
 						m.srcPath = i.path
+						// Camera cards prefill Camera/Reel unless already set
+						cardCfg := config.Config{Source: i.path, Camera: m.config.Camera, Reel: m.config.Reel}
+						m.card = offload.PrefillFromCard(&cardCfg)
+						m.config.Camera, m.config.Reel = cardCfg.Camera, cardCfg.Reel
 						m.state = stateSelectingDest
 						m.currentPath = "" // Reset for Dest Browsing to force loadRootsCmd logic if needed, or better yet, trigger loadRootsCmd
 						// We need to trigger loadRootsCmd explicitly for Dest
@@ -517,9 +590,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if !i.isDir {
 							selectedDst = filepath.Dir(i.path)
 						}
-						// Logic: Copy src to dst/srcBase
+						// Logic: Copy src to dst/srcBase, or dst/<template> if one is set
 						fileName := filepath.Base(m.srcPath)
+						if m.config.DestTemplate != "" {
+							tmplCfg := *m.config
+							tmplCfg.Source = m.srcPath
+							rel, err := offload.ExpandDestTemplate(&tmplCfg, m.srcPath, time.Now())
+							if err != nil {
+								m.err = err
+								m.status = err.Error()
+								return m, nil
+							}
+							fileName = rel
+						}
 						finalDst := filepath.Join(selectedDst, fileName)
+						m.err = nil
 
 						m.dstPaths = append(m.dstPaths, finalDst)
 
@@ -578,7 +663,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if startJob {
 				// Update config with selections
 				m.config.Source = m.srcPath
-				m.config.Destination = m.dstPaths[0]
+				m.config.Destinations = append([]string{}, m.dstPaths...)
 
 				// Auto-enable Resume/SkipExisting if conflict detected
 				if m.destConflict {
@@ -587,14 +672,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				if m.config.DryRun {
 					m.state = stateDryRun
-					return m, performDryRunCmd(m.config, m.dstPaths)
+					return m, performDryRunCmd(m.config)
 				}
 
 				m.state = stateCopying
-				// Create and add job
-				j := job.NewJob(m.config)
-				j.Offloader.Destinations = m.dstPaths
-				m.queue.Add(j)
+				// Create and add job (own copy of the config so later
+				// selections don't leak into queued jobs)
+				jobCfg := *m.config
+				m.queue.Add(job.NewJob(&jobCfg))
 
 				// Wait for queue to pick it up?
 				// Just continue.
@@ -623,21 +708,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.status = status
 
 		// Update Job List
-		active, pending, completed, failed := m.queue.Snapshot()
-		items := []list.Item{}
-		if active != nil {
-			items = append(items, jobItem{j: active})
-		}
-		for _, j := range pending {
-			items = append(items, jobItem{j: j})
-		}
-		for _, j := range completed {
-			items = append(items, jobItem{j: j})
-		}
-		for _, j := range failed {
-			items = append(items, jobItem{j: j})
-		}
-		m.jobList.SetItems(items)
+		m.jobList.SetItems(jobListItems(m.queue))
 
 		return m, waitForQueueState(m.queue.UpdateChan)
 
@@ -657,7 +728,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case job.StatusVerifying:
 			m.state = stateVerifying
 			m.status = jobMsg.Status
-			return m, waitForJobMsg(m.msgChan)
+			m.verifySpeed = jobMsg.Progress.Speed
+			percent := float64(jobMsg.Progress.CopiedBytes) / float64(jobMsg.Progress.TotalBytes)
+			if jobMsg.Progress.TotalBytes == 0 {
+				percent = 0
+			}
+			cmd = m.progress.SetPercent(percent)
+			return m, tea.Batch(cmd, waitForJobMsg(m.msgChan))
 
 		case job.StatusCompleted:
 			m.status = jobMsg.Status
@@ -796,12 +873,21 @@ func (m Model) View() string {
 
 	if m.state == stateSelectingDest {
 		s += fmt.Sprintf("Source Selected: %s\n", m.srcPath)
+		if m.card != nil {
+			s += fmt.Sprintf("Camera Card:     %s (Camera %s, Reel %s)\n", m.card, m.config.Camera, m.config.Reel)
+		}
 		if len(m.dstPaths) > 0 {
 			s += "Destinations Selected:\n"
 			for _, d := range m.dstPaths {
 				s += fmt.Sprintf("  - %s\n", d)
 			}
 		}
+		if m.config.DestTemplate != "" {
+			s += fmt.Sprintf("Folder Template: %s\n", m.config.DestTemplate)
+		}
+		if m.err != nil {
+			s += errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n"
+		}
 		s += "\nBROWSE DESTINATION:\n"
 		s += "(Right/Enter: Open, Left: Back, Space: Select)\n"
 		s += fmt.Sprintf("Path: %s\n\n", m.currentPath)
@@ -815,20 +901,54 @@ func (m Model) View() string {
 		s += fmt.Sprintf("Source: %s\n", m.config.Source)
 		if m.dryRunResult != nil {
 			s += fmt.Sprintf("Files to copy: %d\n", len(m.dryRunResult.Files))
-			s += fmt.Sprintf("Total Size:    %s\n\n", offload.FormatBytes(uint64(m.dryRunResult.TotalSize)))
+			s += fmt.Sprintf("Total Size:    %s\n", offload.FormatBytes(uint64(m.dryRunResult.TotalSize)))
+			if m.config.DestTemplate != "" {
+				s += fmt.Sprintf("Template:      %s\n", m.config.DestTemplate)
+			}
+			if m.dryRunResult.Card != nil {
+				s += fmt.Sprintf("Card:          %s\n", m.dryRunResult.Card)
+			}
+			if n := len(m.dryRunResult.Issues); n > 0 {
+				s += "\n" + errorStyle.Render(fmt.Sprintf("⚠️  Card structure: %d issue(s)", n)) + "\n"
+				for i, issue := range m.dryRunResult.Issues {
+					if i == 10 {
+						s += fmt.Sprintf("  ... and %d more\n", n-i)
+						break
+					}
+					s += fmt.Sprintf("  - %s\n", issue)
+				}
+			}
+			if n := len(m.dryRunResult.Excluded); n > 0 {
+				s += fmt.Sprintf("Excluded:      %d item(s)\n", n)
+				for i, e := range m.dryRunResult.Excluded {
+					if i == 10 {
+						s += fmt.Sprintf("  ... and %d more\n", n-i)
+						break
+					}
+					s += fmt.Sprintf("  - %s (%s)\n", e.RelPath, e.Reason)
+				}
+			}
+			s += "\n"
 			s += "Destinations:\n"
 			for _, d := range m.dryRunResult.Destinations {
-				status := "✅ OK"
-				if !d.CanFit {
+				status, free := "✅ OK", offload.FormatBytes(d.FreeSpace)
+				switch {
+				case !d.SpaceKnown:
+					status, free = "❔ UNKNOWN", "unknown"
+				case !d.CanFit:
 					status = "❌ INSUFFICIENT SPACE"
 				}
 				s += fmt.Sprintf("  - %s\n", d.Path)
-				s += fmt.Sprintf("    Free Space: %s\n", offload.FormatBytes(d.FreeSpace))
+				s += fmt.Sprintf("    Free Space: %s\n", free)
 				s += fmt.Sprintf("    Status:     %s\n", status)
 			}
 		} else {
 			s += "Simulating...\n" + m.spinner.View()
 		}
+		if m.dryRunResult != nil && len(m.dryRunResult.Issues) > 0 && !m.config.IgnoreStructure {
+			s += "\n\n" + instructionStyle.Render("(Press 'o' to copy anyway despite card issues, 'q' to cancel)")
+			return s
+		}
 		s += "\n\n" + instructionStyle.Render("(Press 'c' to continue Copy, 'q' to cancel)")
 		return s
 	}
@@ -856,22 +976,70 @@ func (m Model) View() string {
 		percentageStyle.Render(fmt.Sprintf("%.0f%%", m.progress.Percent()*100)) + "\n"
 
 	if m.state == stateVerifying {
-		s += m.spinner.View() + " " + statsStyle.Render("Verifying Checksums...")
+		speedMB := m.verifySpeed / (1024 * 1024)
+		s += m.spinner.View() + " " + statsStyle.Render(fmt.Sprintf("Verifying Checksums... %.2f MB/s", speedMB))
 	} else if m.CurrentJob != nil {
 		speedMB := m.CurrentJob.Speed / (1024 * 1024)
 		s += statsStyle.Render(fmt.Sprintf("%.2f MB/s • %s", speedMB, m.status))
 	}
 
+	if m.CurrentJob != nil {
+		if m.CurrentJob.State() == job.StatusPaused {
+			s += "\n" + statsStyle.Render("⏸  Paused, files kept open. Press 'p' to continue")
+		}
+		limit := "unlimited"
+		if l := m.CurrentJob.BandwidthLimit(); l > 0 {
+			limit = fmt.Sprintf("%g MB/s", l)
+		}
+		s += "\n" + instructionStyle.Render(fmt.Sprintf("Limit: %s  (+/- adjust, 0 unlimited, p pause, x cancel)", limit))
+	}
+
 	return s
 }
 
+// togglePause pauses j if it's running, or continues it if it's paused
+func (m *Model) togglePause(j *job.Job) {
+	if j.State() == job.StatusPaused {
+		if m.queue.UnpauseJob(j.ID) {
+			m.status = "Continuing..."
+		}
+	} else if m.queue.PauseJob(j.ID) {
+		m.status = "Paused"
+	}
+}
+
+// bwStep is the bandwidth limit step of the +/- keys, in MB/s
+const bwStep = 10
+
+// stepLimit returns the new limit (MB/s) for key. Lowering an unlimited job
+// starts from its current speed; the limit never drops below one step.
+func stepLimit(limit, speedMB float64, key string) float64 {
+	switch key {
+	case "0":
+		return 0
+	case "+", "=":
+		if limit == 0 {
+			return 0
+		}
+		return limit + bwStep
+	case "-":
+		if limit == 0 {
+			limit = math.Floor(speedMB/bwStep) * bwStep
+		} else {
+			limit -= bwStep
+		}
+		return math.Max(limit, bwStep)
+	}
+	return limit
+}
+
 type dryRunResultMsg struct {
 	result *offload.DryRunResult
 }
 
-func performDryRunCmd(cfg *config.Config, dests []string) tea.Cmd {
+func performDryRunCmd(cfg *config.Config) tea.Cmd {
 	return func() tea.Msg {
-		o := offload.NewOffloaderWithConfig(cfg, cfg.Source, dests...)
+		o := offload.NewOffloaderWithConfig(cfg, cfg.Source, cfg.Destinations...)
 		res, err := o.DryRun()
 		if err != nil {
 			return errMsg{err}
@@ -114,7 +114,7 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Create a clean copy of config to avoid state persistence
 				cleanCfg := *m.config
 				cleanCfg.Source = ""
-				cleanCfg.Destination = ""
+				cleanCfg.Destinations = nil
 
 				// Reset existing model state instead of recreating
 				m.copy.Reset(&cleanCfg)
@@ -216,7 +216,10 @@ func renderReadme() string {
     [Esc/q]     Back / Settings / Menu
     [Tab]       Toggle Job Manager
     [x/X]       Cancel Active Job
-    [r/R]       Retry Failed Job
+    [p/P]       Pause / Continue Active Job
+    [+/-]       Raise / Lower Bandwidth Limit
+    [0]         Remove Bandwidth Limit
+    [r/R]       Retry / Resume Job
     [Ctrl+C]    Quit Application
 
     CLI COMMANDS
@@ -225,6 +228,7 @@ func renderReadme() string {
     loot --version       Show Version
     loot /src /dst       Positional Mode
     loot --source <src> --dest <dst>
+    loot verify <dst>    Re-check against MHL
 
     FLAGS
     
@@ -238,6 +242,8 @@ func renderReadme() string {
       
       --metadata-mode    hybrid|header|exiftool|off
       --concurrency <N>  Set workers (Default: 4)
+      --bwlimit <MB/s>   Cap the transfer rate
+      --dest-bwlimit PATH=MB/s  Cap one destination
       --json             Output JSON
       --quiet            Errors only
     `
@@ -251,15 +257,30 @@ func renderVolumeInfo() string {
 	}
 
 	s := "MOUNTED VOLUMES\n\n"
-	s += fmt.Sprintf("%-25s %-10s %-10s %-10s %s\n", "NAME", "TOTAL", "USED", "FREE", "PATH")
-	s += "--------------------------------------------------------------------------------\n"
+	s += fmt.Sprintf("%-25s %-8s %-3s %-10s %-10s %-10s %-28s %s\n", "NAME", "TYPE", "RO", "TOTAL", "USED", "FREE", "CARD", "PATH")
+	s += "---------------------------------------------------------------------------------------------------------------------------\n"
 
 	for _, v := range volumes {
-		s += fmt.Sprintf("%-25s %-10s %-10s %-10s %s\n",
+		card := "-"
+		if v.Card != nil {
+			card = v.Card.String()
+		}
+		fsType := v.FSType
+		if fsType == "" {
+			fsType = "-"
+		}
+		ro := ""
+		if v.ReadOnly {
+			ro = "yes"
+		}
+		s += fmt.Sprintf("%-25s %-8s %-3s %-10s %-10s %-10s %-28s %s\n",
 			truncate(v.Name, 24),
+			truncate(fsType, 8),
+			ro,
 			offload.FormatBytes(v.Total),
 			offload.FormatBytes(v.Used),
 			offload.FormatBytes(v.Free),
+			truncate(card, 27),
 			v.Path,
 		)
 	}
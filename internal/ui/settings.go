@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"loot/internal/config"
 
@@ -25,6 +26,7 @@ type SettingsModel struct {
 	height    int
 	textInput textinput.Model
 	editing   bool
+	message   string // Outcome of the last preset save
 }
 
 func InitialSettingsModel(cfg *config.Config) SettingsModel {
@@ -32,9 +34,13 @@ func InitialSettingsModel(cfg *config.Config) SettingsModel {
 		settingsItem{title: "Job Name", desc: "Set job name for report metadata (Enter to edit)"},
 		settingsItem{title: "Camera", desc: "Set camera identifier (Enter to edit)"},
 		settingsItem{title: "Reel", desc: "Set reel identifier (Enter to edit)"},
+		settingsItem{title: "Shoot Day", desc: "Set shoot day, e.g. D03 (Enter to edit)"},
+		settingsItem{title: "Folder Template", desc: "Destination sub-folders, e.g. {project}/{date}/{camera}_{reel}/{card} (Enter to edit)"},
 		settingsItem{title: "Hash Algorithm", desc: "Select checksum algorithm (Space/Enter to cycle)"},
 		settingsItem{title: "Metadata Mode", desc: "Select extraction strategy (Space/Enter to cycle)"},
 		settingsItem{title: "Dry Run Mode", desc: "Simulate transfer without copying (Space/Enter to toggle)"},
+		settingsItem{title: "Ignore Card Issues", desc: "Copy cards with missing segments, sidecars or zero-byte files (Space/Enter to toggle)"},
+		settingsItem{title: "Save as Preset", desc: "Save these settings as a named preset in the config file (Enter)"},
 	}
 
 	const defaultWidth = 20
@@ -48,7 +54,7 @@ func InitialSettingsModel(cfg *config.Config) SettingsModel {
 
 	ti := textinput.New()
 	ti.Placeholder = "Enter value..."
-	ti.CharLimit = 50
+	ti.CharLimit = 100
 	ti.Width = 30
 
 	return SettingsModel{
@@ -82,6 +88,12 @@ func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
 						m.config.Camera = m.textInput.Value()
 					case "Reel":
 						m.config.Reel = m.textInput.Value()
+					case "Shoot Day":
+						m.config.ShootDay = m.textInput.Value()
+					case "Folder Template":
+						m.config.DestTemplate = m.textInput.Value()
+					case "Save as Preset":
+						m.message = m.savePreset(m.textInput.Value())
 					}
 				}
 				m.editing = false
@@ -113,6 +125,8 @@ func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
 					m.cycleMetadataMode()
 				case "Dry Run Mode":
 					m.config.DryRun = !m.config.DryRun
+				case "Ignore Card Issues":
+					m.config.IgnoreStructure = !m.config.IgnoreStructure
 				case "Job Name":
 					m.editing = true
 					m.textInput.SetValue(m.config.JobName)
@@ -131,6 +145,24 @@ func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
 					m.textInput.Placeholder = "Enter reel ID..."
 					m.textInput.Focus()
 					return m, textinput.Blink
+				case "Shoot Day":
+					m.editing = true
+					m.textInput.SetValue(m.config.ShootDay)
+					m.textInput.Placeholder = "Enter shoot day..."
+					m.textInput.Focus()
+					return m, textinput.Blink
+				case "Folder Template":
+					m.editing = true
+					m.textInput.SetValue(m.config.DestTemplate)
+					m.textInput.Placeholder = "{project}/{date}/{camera}_{reel}/{card}"
+					m.textInput.Focus()
+					return m, textinput.Blink
+				case "Save as Preset":
+					m.editing = true
+					m.textInput.SetValue(m.config.Preset)
+					m.textInput.Placeholder = "Enter preset name (e.g. ARRI-dailies)..."
+					m.textInput.Focus()
+					return m, textinput.Blink
 				}
 			}
 			return m, nil
@@ -146,6 +178,19 @@ func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
 	return m, cmd
 }
 
+// savePreset writes the current settings to the user-level config file
+func (m *SettingsModel) savePreset(name string) string {
+	path, err := config.UserConfigPath()
+	if err == nil {
+		err = config.SavePreset(path, name, m.config)
+	}
+	if err != nil {
+		return fmt.Sprintf("Could not save preset: %v", err)
+	}
+	m.config.Preset = name
+	return fmt.Sprintf("Saved preset %q to %s (use --preset %s)", name, path, name)
+}
+
 func (m *SettingsModel) cycleHashAlgo() {
 	current := m.config.Algorithm
 	switch current {
@@ -178,9 +223,13 @@ func (m *SettingsModel) cycleMetadataMode() {
 
 func (m SettingsModel) View() string {
 	if m.editing {
+		title := "EDIT JOB NAME"
+		if i, ok := m.list.SelectedItem().(settingsItem); ok {
+			title = "EDIT " + strings.ToUpper(i.title)
+		}
 		return fmt.Sprintf(
 			"\n%s\n\n%s\n\n%s",
-			titleStyle.Render("EDIT JOB NAME"),
+			titleStyle.Render(title),
 			m.textInput.View(),
 			instructionStyle.Render("(Enter and Save, Esc to Cancel)"),
 		)
@@ -216,8 +265,21 @@ func (m SettingsModel) View() string {
 		titleStyle.Render(string(m.config.Algorithm)),
 		titleStyle.Render(m.config.MetadataMode),
 		titleStyle.Render(dryRunStatus))
+	if m.config.DestTemplate != "" {
+		status += fmt.Sprintf("\nFolder Template: %s", titleStyle.Render(m.config.DestTemplate))
+	}
+	if m.config.IgnoreStructure {
+		status += fmt.Sprintf("\nCard Issues:    %s", titleStyle.Render("IGNORED"))
+	}
+	if m.config.Preset != "" {
+		status += fmt.Sprintf("\nPreset:         %s", titleStyle.Render(m.config.Preset))
+	}
 	s += "\n" + status
 
+	if m.message != "" {
+		s += "\n\n" + m.message
+	}
+
 	s += "\n\n" + instructionStyle.Render("(Press Space/Enter to change, Esc/q to return)")
 
 	return s